@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/activitywatch"
+	"github.com/austincgause/gametrak/internal/config"
+	"github.com/austincgause/gametrak/internal/models"
+	"github.com/austincgause/gametrak/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var awClient *activitywatch.Client
+
+// awExports tracks sessions still being sent, so they aren't lost when the
+// monitor exits
+var awExports sync.WaitGroup
+
+// awFlushTimeout bounds how long shutdown waits for exports to finish
+const awFlushTimeout = 5 * time.Second
+
+var activityWatchCmd = &cobra.Command{
+	Use:     "activitywatch",
+	Aliases: []string{"aw"},
+	Short:   "Export sessions to ActivityWatch",
+	Long: `Export game sessions to an ActivityWatch server.
+
+When enabled in the config, the monitor sends each logged session as it
+ends:
+
+  activitywatch:
+    enabled: true
+    url: http://localhost:5600   # default
+    bucket: gametrak             # default`,
+}
+
+var activityWatchSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Send the session history to ActivityWatch",
+	Long: `Send every logged session to ActivityWatch, skipping sessions the
+bucket already has. It is safe to run repeatedly.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sessions, err := session.LoadAll(cfg.Settings.SessionsFile)
+		if err != nil {
+			return err
+		}
+
+		client := newAWClient(cfg.ActivityWatch)
+		result, err := client.Sync(sessions)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Sent %d sessions to ActivityWatch (%d already present", result.Sent, result.Skipped)
+		if result.Invalid > 0 {
+			fmt.Printf(", %d invalid", result.Invalid)
+		}
+		fmt.Println(")")
+		return nil
+	},
+}
+
+func newAWClient(aw models.ActivityWatch) *activitywatch.Client {
+	bucket := aw.Bucket
+	if bucket == "" {
+		bucket = config.DefaultAWBucket
+	}
+	return activitywatch.New(awURL(aw), bucket)
+}
+
+func awURL(aw models.ActivityWatch) string {
+	if aw.URL == "" {
+		return config.DefaultAWURL
+	}
+	return aw.URL
+}
+
+// startActivityWatch sets up the live exporter. The server doesn't need to
+// be up yet; the bucket is created with the first session.
+func startActivityWatch() {
+	awClient = newAWClient(cfg.ActivityWatch)
+	slog.Info("exporting sessions to ActivityWatch", "url", awURL(cfg.ActivityWatch))
+}
+
+// exportSession sends a logged session to ActivityWatch in the background
+func exportSession(entry models.SessionLog) {
+	if awClient == nil {
+		return
+	}
+
+	event, err := activitywatch.FromSession(entry)
+	if err != nil {
+		slog.Warn("failed to export session to ActivityWatch", "error", err)
+		return
+	}
+
+	awExports.Add(1)
+	go func() {
+		defer awExports.Done()
+		if err := awClient.Insert([]activitywatch.Event{event}); err != nil {
+			slog.Warn("failed to export session to ActivityWatch", "error", err)
+		}
+	}()
+}
+
+// flushActivityWatch waits briefly for sessions still being exported. It
+// is called before exiting, which would drop them.
+func flushActivityWatch() {
+	done := make(chan struct{})
+	go func() {
+		awExports.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(awFlushTimeout):
+		slog.Warn("gave up waiting for sessions to reach ActivityWatch")
+	}
+}
+
+func init() {
+	activityWatchCmd.AddCommand(activityWatchSyncCmd)
+	rootCmd.AddCommand(activityWatchCmd)
+}
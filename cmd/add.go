@@ -4,14 +4,18 @@ import (
 	"fmt"
 
 	"github.com/austincgause/gametrak/internal/config"
+	"github.com/austincgause/gametrak/internal/gamemode"
 	"github.com/austincgause/gametrak/internal/hyprland"
+	"github.com/austincgause/gametrak/internal/launchers"
 	"github.com/austincgause/gametrak/internal/models"
 	"github.com/spf13/cobra"
 )
 
 var (
-	gameName   string
-	gamePrefix bool
+	gameName      string
+	gamePrefix    bool
+	addFrom       string
+	addDiscovered bool
 )
 
 var addCmd = &cobra.Command{
@@ -21,12 +25,32 @@ var addCmd = &cobra.Command{
 
 The window class can be found by running 'hyprctl clients' while the game is open.
 
+With --from, every installed game in a Lutris, Heroic or Bottles library
+is added at once, using the class its executable is expected to get.
+
+With --discovered, the games the monitor found through GameMode with
+auto_discover: add are added to the configuration.
+
 Examples:
   gametrak add Terraria.bin.x86_64
   gametrak add Terraria.bin.x86_64 --name "Terraria"
-  gametrak add factorio --prefix --name "Factorio"`,
-	Args: cobra.ExactArgs(1),
+  gametrak add factorio --prefix --name "Factorio"
+  gametrak add --from lutris
+  gametrak add --discovered`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if addFrom != "" || addDiscovered {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if addFrom != "" {
+			return addFromLauncher(addFrom)
+		}
+		if addDiscovered {
+			return addDiscoveredGames()
+		}
+
 		class := args[0]
 
 		game := models.Game{
@@ -45,18 +69,104 @@ Examples:
 		}
 		fmt.Println(")")
 
-		// Reload config and regenerate games.conf
-		if err := config.Load(&cfg); err != nil {
-			return fmt.Errorf("failed to reload config: %w", err)
-		}
+		return regenerateGamesConf()
+	},
+}
 
-		if err := hyprland.GenerateGamesConf(cfg.Games, cfg.Settings.HyprlandConf); err != nil {
-			return fmt.Errorf("failed to regenerate games.conf: %w", err)
+// addFromLauncher adds every installed game with a known executable from a
+// launcher's library
+func addFromLauncher(source string) error {
+	library, err := launchers.Load(source)
+	if err != nil {
+		return err
+	}
+
+	var games []models.Game
+	unknown := 0
+	for _, g := range library {
+		if g.Class == "" {
+			unknown++
+			continue
 		}
+		games = append(games, models.Game{Class: g.Class, Name: g.Name})
+	}
+
+	added, err := config.AddGames(games)
+	if err != nil {
+		return err
+	}
+
+	for _, g := range added {
+		fmt.Printf("Added game: %s (class: %s)\n", g.DisplayName(), g.Class)
+	}
+	fmt.Printf("Added %d of %d games from %s", len(added), len(library), source)
+	if skipped := len(games) - len(added); skipped > 0 {
+		fmt.Printf(", %d already configured", skipped)
+	}
+	if unknown > 0 {
+		fmt.Printf(", %d not installed or without a known executable", unknown)
+	}
+	fmt.Println()
 
-		fmt.Printf("Regenerated %s\n", cfg.Settings.HyprlandConf)
+	if len(added) == 0 {
 		return nil
-	},
+	}
+	return regenerateGamesConf()
+}
+
+// addDiscoveredGames adds the games recorded from GameMode and clears them
+// from the state directory
+func addDiscoveredGames() error {
+	discovered, err := gamemode.LoadDiscovered(config.DefaultDiscovered)
+	if err != nil {
+		return err
+	}
+	if len(discovered) == 0 {
+		fmt.Println("No discovered games to add")
+		return nil
+	}
+
+	games := make([]models.Game, len(discovered))
+	for i, d := range discovered {
+		games[i] = models.Game{Class: d.Class, Name: d.Name}
+	}
+
+	added, err := config.AddGames(games)
+	if err != nil {
+		return err
+	}
+	if err := gamemode.SaveDiscovered(config.DefaultDiscovered, nil); err != nil {
+		return err
+	}
+
+	for _, g := range added {
+		fmt.Printf("Added game: %s (class: %s)\n", g.DisplayName(), g.Class)
+	}
+	fmt.Printf("Added %d of %d discovered games", len(added), len(games))
+	if skipped := len(games) - len(added); skipped > 0 {
+		fmt.Printf(", %d already configured", skipped)
+	}
+	fmt.Println()
+
+	if len(added) == 0 {
+		return nil
+	}
+	return regenerateGamesConf()
+}
+
+// regenerateGamesConf reloads the config after games were added and
+// rewrites games.conf to match
+func regenerateGamesConf() error {
+	if err := config.Load(&cfg); err != nil {
+		return fmt.Errorf("failed to reload config: %w", err)
+	}
+
+	if err := hyprland.GenerateGamesConf(cfg.Games, cfg.Settings.HyprlandConf); err != nil {
+		return fmt.Errorf("failed to regenerate games.conf: %w", err)
+	}
+
+	fmt.Printf("Regenerated %s\n", cfg.Settings.HyprlandConf)
+	return nil
 }
 
 func init() {
@@ -64,4 +174,7 @@ func init() {
 
 	addCmd.Flags().StringVarP(&gameName, "name", "n", "", "display name for the game")
 	addCmd.Flags().BoolVarP(&gamePrefix, "prefix", "p", false, "match as prefix (e.g., steam_app_ matches steam_app_12345)")
+	addCmd.Flags().StringVar(&addFrom, "from", "", "add all installed games from a launcher: lutris, heroic or bottles")
+	addCmd.Flags().BoolVar(&addDiscovered, "discovered", false, "add the games discovered through GameMode")
+	addCmd.MarkFlagsMutuallyExclusive("from", "discovered")
 }
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/bank"
+	"github.com/austincgause/gametrak/internal/models"
+	"github.com/austincgause/gametrak/internal/utility"
+	"github.com/spf13/cobra"
+)
+
+var bankReason string
+
+var bankCmd = &cobra.Command{
+	Use:   "bank",
+	Short: "Show the time bank balance and transactions",
+	Long: `Show the time bank balance and its transaction history.
+
+The time bank holds extra playtime. Once the global daily budget is
+exceeded, the monitor draws from the bank automatically, extending the
+budget until the balance runs out.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		txs, err := bank.LoadAll(cfg.Settings.BankFile)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Time bank balance: %s\n\n", utility.FormatMinutes(bank.Balance(txs)))
+
+		if len(txs) == 0 {
+			fmt.Println("No transactions yet.")
+			return nil
+		}
+
+		maxLen := 0
+		for _, tx := range txs {
+			if n := len(formatTransaction(tx.Minutes)); n > maxLen {
+				maxLen = n
+			}
+		}
+
+		// Show most recent first
+		for i := len(txs) - 1; i >= 0; i-- {
+			tx := txs[i]
+			date := tx.Time
+			if t, err := time.Parse(time.RFC3339, tx.Time); err == nil {
+				date = t.Format("2006-01-02 15:04")
+			}
+			line := fmt.Sprintf("  %s  %*s", date, maxLen, formatTransaction(tx.Minutes))
+			if tx.Reason != "" {
+				line += "  " + tx.Reason
+			}
+			fmt.Println(line)
+		}
+
+		fmt.Println()
+		return nil
+	},
+}
+
+var bankAddCmd = &cobra.Command{
+	Use:   "add <duration>",
+	Short: "Credit playtime to the time bank",
+	Long: `Credit playtime to the time bank.
+
+The duration may be given in minutes or as a Go duration.
+
+Examples:
+  gametrak bank add 30 --reason chores
+  gametrak bank add 1h30m --reason "finished homework"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mins, err := parseMinutes(args[0])
+		if err != nil {
+			return err
+		}
+		if mins <= 0 {
+			return fmt.Errorf("duration must be positive: %s", args[0])
+		}
+
+		tx := models.BankTransaction{
+			Time:    time.Now().Format(time.RFC3339),
+			Minutes: mins,
+			Reason:  bankReason,
+		}
+		if err := bank.Append(cfg.Settings.BankFile, tx); err != nil {
+			return err
+		}
+
+		txs, err := bank.LoadAll(cfg.Settings.BankFile)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Added %s to the time bank (balance: %s)\n",
+			utility.FormatMinutes(mins), utility.FormatMinutes(bank.Balance(txs)))
+		return nil
+	},
+}
+
+// parseMinutes parses a plain number of minutes or a Go duration such as
+// 1h30m. Durations that aren't whole minutes are rejected rather than cut
+// short.
+func parseMinutes(s string) (int, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q (e.g. 30, 30m or 1h30m)", s)
+	}
+	if d%time.Minute != 0 {
+		return 0, fmt.Errorf("duration %q is not a whole number of minutes", s)
+	}
+	return int(d / time.Minute), nil
+}
+
+func formatTransaction(mins int) string {
+	if mins > 0 {
+		return "+" + utility.FormatMinutes(mins)
+	}
+	return utility.FormatMinutes(mins)
+}
+
+func init() {
+	rootCmd.AddCommand(bankCmd)
+	bankCmd.AddCommand(bankAddCmd)
+
+	bankAddCmd.Flags().StringVarP(&bankReason, "reason", "r", "", "why the time was earned")
+}
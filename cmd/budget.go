@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/bank"
+	"github.com/austincgause/gametrak/internal/budget"
+	"github.com/austincgause/gametrak/internal/messages"
+	"github.com/austincgause/gametrak/internal/models"
+	"github.com/austincgause/gametrak/internal/notify"
+	"github.com/austincgause/gametrak/internal/session"
+	"github.com/austincgause/gametrak/internal/utility"
+)
+
+var budgetTracker = budget.NewTracker()
+
+// checkBudgets evaluates every configured limit against the logged history
+// plus the active sessions, sends warnings for newly crossed thresholds and,
+// if enforcement is enabled, closes windows of games that are over budget.
+func checkBudgets() {
+	statuses, ok := budgetStatuses()
+	if !ok {
+		return
+	}
+	warnAt := budget.WarnAt(cfg.Budgets)
+
+	for _, st := range statuses {
+		if pct := budgetTracker.Crossed(st, warnAt); pct > 0 {
+			subject, urgency := "Playtime Budget", notify.UrgencyNormal
+			if st.Exceeded() {
+				subject, urgency = "Playtime Budget Reached", notify.UrgencyCritical
+			}
+
+			data := messages.Data{
+				Game:    st.Name(),
+				Subject: subject,
+				Message: fmt.Sprintf("%s - %s of %s %s budget used (%d%%)", st.Name(),
+					utility.FormatDurationRounded(st.Used), utility.FormatDurationRounded(st.Limit),
+					st.Period, st.Percent()),
+			}
+			msg := messages.Render(messages.Reminder, data)
+			printMessage(msg)
+			notifyMessage(notify.EventReminder, urgency, msg)
+		}
+
+		if cfg.Budgets.Enforce && st.Exceeded() {
+			closeOverBudget(st)
+		}
+	}
+}
+
+// seedBudgetWarnings marks the warnings already due when the monitor starts
+// as sent, since they were given before it was restarted
+func seedBudgetWarnings() {
+	if statuses, ok := budgetStatuses(); ok {
+		budgetTracker.Seed(statuses, budget.WarnAt(cfg.Budgets))
+	}
+}
+
+// budgetStatuses evaluates every configured limit against the logged
+// history plus the active sessions. It reports false if there are no
+// limits or the history can't be read.
+func budgetStatuses() ([]budget.Status, bool) {
+	if !budget.Configured(cfg) {
+		return nil, false
+	}
+
+	logs, err := session.LoadAll(cfg.Settings.SessionsFile)
+	if err != nil {
+		slog.Warn("failed to load sessions for budget check", "error", err)
+		return nil, false
+	}
+
+	now := time.Now()
+	active := activeSessionList()
+	extra := drawFromBank(logs, active, now)
+	return budget.Evaluate(cfg, logs, active, now, extra), true
+}
+
+// drawFromBank debits the time bank for any playtime beyond a daily budget,
+// global or per-game, that hasn't been paid for yet. It returns how far the
+// daily budgets are extended: what has been drawn today, plus a reserve from
+// the balance for active sessions shorter than min_session_mins. Those are
+// discarded if they end now, so they aren't drawn for until they would be
+// logged.
+func drawFromBank(logs []models.SessionLog, active []models.Session, now time.Time) time.Duration {
+	startOfDay := utility.StartOfDay(now)
+	overDaily, ok := dailyOverage(logs, startOfDay, now)
+	if !ok {
+		return 0
+	}
+
+	txs, err := bank.LoadAll(cfg.Settings.BankFile)
+	if err != nil {
+		slog.Warn("failed to load time bank", "error", err)
+		return 0
+	}
+
+	balance := bank.Balance(txs)
+	drawn := bank.DrawnSince(txs, startOfDay)
+
+	minDuration := time.Duration(cfg.Settings.MinSessionMins) * time.Minute
+	var counted, pending []models.Session
+	for _, s := range active {
+		if now.Sub(s.StartTime) >= minDuration {
+			counted = append(counted, s)
+		} else {
+			pending = append(pending, s)
+		}
+	}
+
+	// Playtime past the budget is paid for a whole minute ahead, so a game
+	// isn't closed between draws while the bank still has time in it
+	over := overDaily(counted)
+	if owed := min(int(over/time.Minute)+1-drawn, balance); over >= 0 && owed > 0 {
+		tx := models.BankTransaction{
+			Time:    now.Format(time.RFC3339),
+			Minutes: -owed,
+			Reason:  "daily budget exceeded",
+			Auto:    true,
+		}
+		if err := bank.Append(cfg.Settings.BankFile, tx); err != nil {
+			slog.Warn("failed to draw from time bank", "error", err)
+		} else {
+			drawn += owed
+			balance -= owed
+			slog.Info("drew from time bank", "used", utility.FormatMinutes(owed), "balance", utility.FormatMinutes(balance))
+		}
+	}
+
+	extension := time.Duration(drawn) * time.Minute
+	if over := overDaily(active); len(pending) > 0 && over >= 0 {
+		unpaid := over + time.Minute - extension
+		extension += min(max(unpaid, 0), time.Duration(max(balance, 0))*time.Minute)
+	}
+	return extension
+}
+
+// dailyOverage returns a function measuring how far today's playtime with
+// the given active sessions is past the most exceeded daily budget. It
+// reports false if no daily budget is configured.
+func dailyOverage(logs []models.SessionLog, startOfDay, now time.Time) (func([]models.Session) time.Duration, bool) {
+	type dailyLimit struct {
+		limit time.Duration
+		match func(class string) bool
+	}
+
+	var limits []dailyLimit
+	if cfg.Budgets.DailyMins > 0 {
+		limits = append(limits, dailyLimit{time.Duration(cfg.Budgets.DailyMins) * time.Minute, allClasses})
+	}
+	for i := range cfg.Games {
+		game := cfg.Games[i]
+		if game.Limits.DailyMins <= 0 {
+			continue
+		}
+		limits = append(limits, dailyLimit{
+			limit: time.Duration(game.Limits.DailyMins) * time.Minute,
+			match: func(class string) bool { return budget.BelongsTo(class, game, cfg.Games) },
+		})
+	}
+	if len(limits) == 0 {
+		return nil, false
+	}
+
+	return func(active []models.Session) time.Duration {
+		worst := time.Duration(math.MinInt64)
+		for _, l := range limits {
+			worst = max(worst, budget.Tally(logs, active, startOfDay, now, l.match)-l.limit)
+		}
+		return worst
+	}, true
+}
+
+// closeOverBudget closes every active game that counts against an
+// exceeded limit
+func closeOverBudget(st budget.Status) {
+	for address, sess := range activeSessions {
+		if !st.Applies(sess.Class, cfg.Games) {
+			continue
+		}
+
+		slog.Info("closing game over budget", "game", sess.GameName, "budget", st.Name(), "period", st.Period)
+		closeSession(address, sess)
+	}
+}
+
+// activeSessionList returns a snapshot of the sessions currently being tracked
+func activeSessionList() []models.Session {
+	list := make([]models.Session, 0, len(activeSessions))
+	for _, sess := range activeSessions {
+		list = append(list, *sess)
+	}
+	return list
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/bank"
+	"github.com/austincgause/gametrak/internal/models"
+)
+
+// withBank points the global config at a fresh bank holding balance minutes
+func withBank(t *testing.T, c models.Config, balance int) {
+	t.Helper()
+	saved := cfg
+	t.Cleanup(func() { cfg = saved })
+
+	c.Settings.BankFile = filepath.Join(t.TempDir(), "bank.jsonl")
+	cfg = c
+	if balance > 0 {
+		tx := models.BankTransaction{Time: time.Now().AddDate(0, 0, -1).Format(time.RFC3339), Minutes: balance}
+		if err := bank.Append(c.Settings.BankFile, tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func playedToday(class string, now time.Time, d time.Duration) models.SessionLog {
+	start := now.Add(-d)
+	return models.SessionLog{
+		Class:           class,
+		Start:           start.Format(time.RFC3339),
+		End:             now.Format(time.RFC3339),
+		DurationSeconds: int64(d.Seconds()),
+	}
+}
+
+func TestDrawFromBank(t *testing.T) {
+	now := time.Date(2025, 3, 5, 20, 0, 0, 0, time.Local)
+	global := models.Config{Budgets: models.Budgets{Limits: models.Limits{DailyMins: 60}}}
+	perGame := models.Config{Games: []models.Game{{Class: "factorio", Limits: models.Limits{DailyMins: 60}}}}
+
+	for _, tc := range []struct {
+		name    string
+		config  models.Config
+		played  time.Duration
+		class   string
+		balance int
+		drawn   int // minutes taken from the bank
+	}{
+		{"under budget", global, 59 * time.Minute, "factorio", 30, 0},
+		{"at budget", global, 60 * time.Minute, "factorio", 30, 1},
+		{"rounds up a part minute", global, 70*time.Minute + 30*time.Second, "factorio", 30, 11},
+		{"limited by balance", global, 90 * time.Minute, "factorio", 5, 5},
+		{"empty bank", global, 90 * time.Minute, "factorio", 0, 0},
+		{"per-game budget", perGame, 70 * time.Minute, "factorio", 30, 11},
+		{"other game", perGame, 70 * time.Minute, "terraria", 30, 0},
+		{"no daily budget", models.Config{}, 90 * time.Minute, "factorio", 30, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			withBank(t, tc.config, tc.balance)
+			logs := []models.SessionLog{playedToday(tc.class, now, tc.played)}
+
+			extension := drawFromBank(logs, nil, now)
+			if want := time.Duration(tc.drawn) * time.Minute; extension != want {
+				t.Errorf("extension = %s, want %s", extension, want)
+			}
+
+			txs, err := bank.LoadAll(cfg.Settings.BankFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tc.balance - bank.Balance(txs); got != tc.drawn {
+				t.Errorf("drew %d minutes, want %d", got, tc.drawn)
+			}
+
+			// Drawing again without more playtime takes nothing further
+			drawFromBank(logs, nil, now)
+			txs, _ = bank.LoadAll(cfg.Settings.BankFile)
+			if got := tc.balance - bank.Balance(txs); got != tc.drawn {
+				t.Errorf("second draw took the total to %d minutes, want %d", got, tc.drawn)
+			}
+		})
+	}
+}
+
+func TestDrawFromBankReservesForShortSessions(t *testing.T) {
+	now := time.Date(2025, 3, 5, 20, 0, 0, 0, time.Local)
+	c := models.Config{Budgets: models.Budgets{Limits: models.Limits{DailyMins: 60}}}
+	c.Settings.MinSessionMins = 5
+	withBank(t, c, 30)
+
+	logs := []models.SessionLog{playedToday("factorio", now, 59*time.Minute)}
+	active := []models.Session{{Class: "factorio", StartTime: now.Add(-2 * time.Minute)}}
+
+	// The short session isn't paid for yet, but the budget is extended to
+	// cover it and the minute ahead
+	if extension := drawFromBank(logs, active, now); extension != 2*time.Minute {
+		t.Errorf("extension = %s, want 2m", extension)
+	}
+	txs, _ := bank.LoadAll(cfg.Settings.BankFile)
+	if balance := bank.Balance(txs); balance != 30 {
+		t.Errorf("balance = %d, want nothing drawn for a short session", balance)
+	}
+}
+
+func TestParseMinutes(t *testing.T) {
+	for in, want := range map[string]int{
+		"30":    30,
+		"-15":   -15,
+		"30m":   30,
+		"1h30m": 90,
+		"120s":  2,
+	} {
+		if got, err := parseMinutes(in); err != nil || got != want {
+			t.Errorf("parseMinutes(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+
+	for _, in := range []string{"30s", "90s", "1h30m10s", "soon", ""} {
+		if got, err := parseMinutes(in); err == nil {
+			t.Errorf("parseMinutes(%q) = %d, want an error", in, got)
+		}
+	}
+}
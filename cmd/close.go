@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/austincgause/gametrak/internal/hyprland"
+	"github.com/austincgause/gametrak/internal/models"
+	"github.com/austincgause/gametrak/internal/process"
+)
+
+// closeSession closes the game behind an active session. Sessions from
+// GameMode and gametrak run have no window address of their own, so their
+// windows are found by process or class, and the process is asked to
+// terminate if it has none. gametrak run then logs the session as usual.
+func closeSession(address string, sess *models.Session) {
+	pid, ok := sessionPID(address)
+	if !ok {
+		if err := hyprland.CloseWindow(address); err != nil {
+			slog.Warn("failed to close window", "address", address, "error", err)
+		}
+		return
+	}
+
+	pids := map[int]bool{pid: true}
+	for _, p := range process.Descendants(pid) {
+		pids[p] = true
+	}
+
+	closed := false
+	if clients, err := hyprland.Clients(); err != nil {
+		slog.Debug("failed to list windows", "error", err)
+	} else {
+		for _, c := range clients {
+			if !pids[c.PID] && c.Class != sess.Class {
+				continue
+			}
+			if err := hyprland.CloseWindow(c.Address); err != nil {
+				slog.Warn("failed to close window", "address", c.Address, "error", err)
+				continue
+			}
+			closed = true
+		}
+	}
+
+	if !closed {
+		slog.Info("no window found, terminating game", "game", sess.GameName, "pid", pid)
+		if err := syscall.Kill(pid, syscall.SIGTERM); err != nil {
+			slog.Warn("failed to terminate game", "pid", pid, "error", err)
+		}
+	}
+}
+
+// sessionPID returns the process behind a session's pseudo-address
+func sessionPID(address string) (int, bool) {
+	for _, prefix := range []string{gameModePrefix, runPrefix} {
+		if rest, ok := strings.CutPrefix(address, prefix); ok {
+			pid, err := strconv.Atoi(rest)
+			return pid, err == nil && pid > 0
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/austincgause/gametrak/internal/config"
+	"github.com/austincgause/gametrak/internal/gamemode"
+	"github.com/austincgause/gametrak/internal/models"
+	"github.com/austincgause/gametrak/internal/process"
+	"github.com/austincgause/gametrak/internal/utility"
+)
+
+// gameModePrefix marks the pseudo-addresses of sessions detected through
+// GameMode, which have no window of their own
+const gameModePrefix = "gamemode:"
+
+func gameModeAddress(pid int) string {
+	return fmt.Sprintf("%s%d", gameModePrefix, pid)
+}
+
+// startGameMode subscribes to GameMode registrations. It returns a nil
+// channel if the session bus is unavailable, which never delivers.
+func startGameMode() (*gamemode.Watcher, <-chan gamemode.Event) {
+	switch autoDiscoverPolicy() {
+	case models.AutoDiscoverIgnore, models.AutoDiscoverRecord, models.AutoDiscoverAdd:
+	default:
+		slog.Warn("unknown gamemode auto_discover policy, ignoring unconfigured games",
+			"policy", cfg.GameMode.AutoDiscover)
+		cfg.GameMode.AutoDiscover = models.AutoDiscoverIgnore
+	}
+
+	w, err := gamemode.Watch()
+	if err != nil {
+		slog.Warn("GameMode detection disabled", "error", err)
+		return nil, nil
+	}
+
+	slog.Info("watching GameMode for games", "auto_discover", autoDiscoverPolicy())
+	return w, w.Events()
+}
+
+func autoDiscoverPolicy() string {
+	if cfg.GameMode.AutoDiscover == "" {
+		return models.AutoDiscoverIgnore
+	}
+	return cfg.GameMode.AutoDiscover
+}
+
+// handleGameMode starts or ends a session for a process GameMode reports.
+// Games that already have a window session are left to it.
+func handleGameMode(ev gamemode.Event) {
+	address := gameModeAddress(ev.PID)
+	if !ev.Registered {
+		endSession(address)
+		return
+	}
+	if _, exists := activeSessions[address]; exists {
+		return
+	}
+
+	classes := process.Classes(ev.PID)
+	if len(classes) == 0 {
+		slog.Debug("GameMode game exited before it could be identified", "pid", ev.PID)
+		return
+	}
+
+	class, game, ok := gameModeGame(classes)
+	if !ok {
+		return
+	}
+
+	for _, sess := range activeSessions {
+		if sess.Class == class {
+			return
+		}
+	}
+
+	startSession(address, class, "", game)
+}
+
+// gameModeGame picks the configured game matching one of a process's
+// classes, or applies the auto-discover policy if none match
+func gameModeGame(classes []string) (string, models.Game, bool) {
+	for _, class := range classes {
+		if game, ok := utility.MatchGame(class, cfg.Games); ok {
+			return class, game, true
+		}
+	}
+
+	policy := autoDiscoverPolicy()
+	if policy == models.AutoDiscoverIgnore {
+		slog.Debug("ignoring unconfigured game from GameMode", "class", classes[0])
+		return "", models.Game{}, false
+	}
+
+	class, ok := discoveredClass(classes)
+	if !ok {
+		slog.Debug("ignoring GameMode process that isn't a game", "classes", classes)
+		return "", models.Game{}, false
+	}
+
+	game := models.Game{Class: class, Name: discoveredName(class)}
+	if policy == models.AutoDiscoverAdd {
+		recordDiscoveredGame(game)
+	}
+	return class, game, true
+}
+
+// discoveredClass picks the first of a process's classes that isn't a
+// known launcher, runtime or shell
+func discoveredClass(classes []string) (string, bool) {
+	for _, class := range classes {
+		if gamemode.IsGame(class) {
+			return class, true
+		}
+	}
+	return "", false
+}
+
+// discoveredName names an unconfigured game from the Steam and launcher
+// libraries, falling back to its executable
+func discoveredName(class string) string {
+	if name, ok := steamResolver.Name(class); ok {
+		return name
+	}
+	if name, ok := launcherIndex.Name(class); ok {
+		return name
+	}
+	return strings.TrimSuffix(class, ".exe")
+}
+
+// recordDiscoveredGame remembers an auto-discovered game in the state
+// directory for gametrak add --discovered. The user's config is never
+// written by the monitor, and each class is only saved the first time.
+func recordDiscoveredGame(game models.Game) {
+	games, err := gamemode.LoadDiscovered(config.DefaultDiscovered)
+	if err != nil {
+		slog.Warn("failed to load discovered games", "error", err)
+		return
+	}
+	for _, g := range games {
+		if g.Class == game.Class {
+			return
+		}
+	}
+
+	games = append(games, gamemode.Discovery{Class: game.Class, Name: game.Name})
+	if err := gamemode.SaveDiscovered(config.DefaultDiscovered, games); err != nil {
+		slog.Warn("failed to record discovered game", "game", game.Name, "error", err)
+		return
+	}
+	slog.Info("discovered game, run 'gametrak add --discovered' to keep it",
+		"game", game.Name, "class", game.Class)
+}
+
+// trackedByGameMode reports whether GameMode is already tracking a game
+// with the exact window class
+func trackedByGameMode(class string) bool {
+	for address, sess := range activeSessions {
+		if strings.HasPrefix(address, gameModePrefix) && sess.Class == class {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"log/slog"
+
+	"github.com/austincgause/gametrak/internal/gamescope"
+	"github.com/austincgause/gametrak/internal/hyprland"
+	"github.com/austincgause/gametrak/internal/utility"
+)
+
+// resolveGamescope finds the class of the game running inside a gamescope
+// window by looking at gamescope's child processes. It returns the first
+// candidate that matches a configured game, or false if none do, in which
+// case the window is treated as plain gamescope.
+func resolveGamescope(address string) (string, bool) {
+	pid, err := hyprland.WindowPID(address)
+	if err != nil {
+		slog.Debug("failed to find gamescope process", "address", address, "error", err)
+		return "", false
+	}
+
+	candidates := gamescope.Candidates(pid)
+	for _, class := range candidates {
+		if _, ok := utility.MatchGame(class, cfg.Games); ok {
+			slog.Debug("resolved gamescope window", "address", address, "pid", pid, "class", class)
+			return class, true
+		}
+	}
+
+	slog.Debug("no configured game inside gamescope", "address", address, "pid", pid, "candidates", candidates)
+	return "", false
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -46,6 +47,8 @@ Time filters: today, yesterday, week, month, year, or a specific date (YYYY-MM-D
 		// Apply filters
 		sessions = filterSessions(sessions, timeFilter, gameFilter)
 
+		sortByStart(sessions)
+
 		if len(sessions) == 0 {
 			fmt.Println("No sessions match the filter criteria.")
 			return nil
@@ -87,8 +90,11 @@ Time filters: today, yesterday, week, month, year, or a specific date (YYYY-MM-D
 				hours: r.Hours,
 				mins:  r.Mins,
 			}
-			if len(s.Game) > maxGameLen {
-				maxGameLen = len(s.Game)
+			if s.Source != "" {
+				row.game += " (" + s.Source + ")"
+			}
+			if len(row.game) > maxGameLen {
+				maxGameLen = len(row.game)
 			}
 			if r.Hours > 0 {
 				hasHours = true
@@ -181,25 +187,28 @@ func filterSessions(sessions []models.SessionLog, timeFilter, gameFilter string)
 	return filtered
 }
 
+// sortByStart orders sessions oldest first. Sessions added by hand are
+// appended out of order.
+func sortByStart(sessions []models.SessionLog) {
+	sort.SliceStable(sessions, func(i, j int) bool {
+		a, _ := time.Parse(time.RFC3339, sessions[i].Start)
+		b, _ := time.Parse(time.RFC3339, sessions[j].Start)
+		return a.Before(b)
+	})
+}
+
 func matchesTimeFilter(startTime, now time.Time, filter string) bool {
 	loc := now.Location()
 
 	switch filter {
 	case "today":
-		y, m, d := now.Date()
-		startOfDay := time.Date(y, m, d, 0, 0, 0, 0, loc)
-		return !startTime.Before(startOfDay)
+		return !startTime.Before(utility.StartOfDay(now))
 	case "yesterday":
-		y, m, d := now.AddDate(0, 0, -1).Date()
-		startOfYesterday := time.Date(y, m, d, 0, 0, 0, 0, loc)
-		y, m, d = now.Date()
-		startOfToday := time.Date(y, m, d, 0, 0, 0, 0, loc)
+		startOfYesterday := utility.StartOfDay(now.AddDate(0, 0, -1))
+		startOfToday := utility.StartOfDay(now)
 		return !startTime.Before(startOfYesterday) && startTime.Before(startOfToday)
 	case "week":
-		startOfWeek := now.AddDate(0, 0, -int(now.Weekday()))
-		y, m, d := startOfWeek.Date()
-		startOfWeek = time.Date(y, m, d, 0, 0, 0, 0, loc)
-		return !startTime.Before(startOfWeek)
+		return !startTime.Before(utility.StartOfWeek(now))
 	case "month":
 		y, m, _ := now.Date()
 		startOfMonth := time.Date(y, m, 1, 0, 0, 0, 0, loc)
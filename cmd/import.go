@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/baseline"
+	"github.com/austincgause/gametrak/internal/config"
+	"github.com/austincgause/gametrak/internal/launchers"
+	"github.com/austincgause/gametrak/internal/models"
+	"github.com/austincgause/gametrak/internal/session"
+	"github.com/austincgause/gametrak/internal/steam"
+	"github.com/austincgause/gametrak/internal/utility"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import playtime recorded by other launchers",
+	Long: `Import playtime recorded by other launchers as a baseline.
+
+Baselines count towards all-time totals in stats, but not towards
+period-filtered views. Time gametrak already logged for a game is
+subtracted so it isn't counted twice. A game imported from more than
+one launcher counts the largest of its imported totals. Importing again
+replaces the previous import from the same launcher.`,
+}
+
+var importSteamCmd = &cobra.Command{
+	Use:   "steam",
+	Short: "Import playtime from the local Steam installation",
+	Long: `Import per-game playtime from Steam's localconfig.vdf.
+
+Games are named from their app manifest, so only installed games can be
+imported.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resolver := steam.NewResolver(cfg.Settings.SteamDir, config.DefaultSteamCache)
+		if resolver.Root() == "" {
+			return fmt.Errorf("no Steam installation found; set steam_dir in the config")
+		}
+
+		playtime, err := steam.Playtime(resolver.Root())
+		if err != nil {
+			return err
+		}
+
+		imported := make(map[string]int64)
+		ids := make(map[string]string)
+		skipped := 0
+		for id, mins := range playtime {
+			name, ok := resolver.NameForID(id)
+			if !ok {
+				skipped++
+				continue
+			}
+			imported[name] += int64(mins) * 60
+			ids[name] = id
+		}
+
+		if err := saveBaselines("steam", imported, ids); err != nil {
+			return err
+		}
+
+		fmt.Printf("Imported Steam playtime for %d games", len(imported))
+		if skipped > 0 {
+			fmt.Printf(" (%d not installed, skipped)", skipped)
+		}
+		fmt.Println()
+		return nil
+	},
+}
+
+// saveBaselines records imported playtime per game for a source, less what
+// the session log already has for each game, and prints what was stored.
+// ids optionally maps games to the source's own identifiers.
+func saveBaselines(source string, imported map[string]int64, ids map[string]string) error {
+	sessions, err := session.LoadAll(cfg.Settings.SessionsFile)
+	if err != nil {
+		return err
+	}
+	logged := baseline.LoggedSeconds(sessions)
+
+	existing, err := baseline.Load(cfg.Settings.BaselinesFile)
+	if err != nil {
+		return err
+	}
+
+	games := make([]string, 0, len(imported))
+	for game := range imported {
+		games = append(games, game)
+	}
+	sort.Strings(games)
+
+	now := time.Now().Format(time.RFC3339)
+	var baselines []models.Baseline
+	for _, game := range games {
+		secs := imported[game] - logged[game]
+		if secs <= 0 {
+			continue
+		}
+		baselines = append(baselines, models.Baseline{
+			Game:     game,
+			Source:   source,
+			SourceID: ids[game],
+			Seconds:  secs,
+			Imported: now,
+		})
+		fmt.Printf("  %s  %s\n", game, utility.FormatDurationRounded(time.Duration(secs)*time.Second))
+	}
+
+	return baseline.Save(cfg.Settings.BaselinesFile, baseline.Replace(existing, source, baselines))
+}
+
+var importLutrisCmd = &cobra.Command{
+	Use:   "lutris",
+	Short: "Import playtime from Lutris",
+	Long: `Import per-game playtime from Lutris's pga.db. Reading the database
+requires the sqlite3 command.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return importLauncher(launchers.Lutris)
+	},
+}
+
+var importHeroicCmd = &cobra.Command{
+	Use:   "heroic",
+	Short: "Import playtime from Heroic Games Launcher",
+	Long:  `Import per-game playtime tracked by Heroic Games Launcher.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return importLauncher(launchers.Heroic)
+	},
+}
+
+// importLauncher stores the playtime a launcher recorded as baselines
+func importLauncher(source string) error {
+	library, err := launchers.Load(source)
+	if err != nil {
+		return err
+	}
+
+	imported := make(map[string]int64)
+	ids := make(map[string]string)
+	for _, g := range library {
+		if g.PlaytimeSeconds <= 0 {
+			continue
+		}
+		imported[g.Name] += g.PlaytimeSeconds
+		ids[g.Name] = g.ID
+	}
+
+	if err := saveBaselines(source, imported, ids); err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %s playtime for %d games\n", source, len(imported))
+	return nil
+}
+
+func init() {
+	importCmd.AddCommand(importSteamCmd)
+	importCmd.AddCommand(importLutrisCmd)
+	importCmd.AddCommand(importHeroicCmd)
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/models"
+	"github.com/austincgause/gametrak/internal/session"
+	"github.com/austincgause/gametrak/internal/utility"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logStart    string
+	logEnd      string
+	logDuration string
+)
+
+var logCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Edit the session log by hand",
+}
+
+var logAddCmd = &cobra.Command{
+	Use:   "add <game>",
+	Short: "Record a session that wasn't tracked",
+	Long: `Add a session after the fact, for play gametrak missed.
+
+Give the start and either the end or the duration. Times can be dates
+("2026-10-14 20:00"), a time of day, optionally after today, yesterday or
+a weekday ("yesterday 20:00", "friday 8pm"), or relative ("3h ago").
+
+The session is refused if it overlaps one already logged for the game. A
+name matching a configured game is recorded as that game.
+
+Examples:
+  gametrak log add "Elden Ring" --start "2026-10-14 20:00" --duration 2h30m
+  gametrak log add Wingspan --start "yesterday 19:00" --end "yesterday 21:15"
+  gametrak log add Factorio --start "3h ago" --end now`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if logStart == "" {
+			return fmt.Errorf("--start is required")
+		}
+		if (logEnd == "") == (logDuration == "") {
+			return fmt.Errorf("give exactly one of --end or --duration")
+		}
+
+		now := time.Now()
+		start, err := utility.ParseTime(logStart, now)
+		if err != nil {
+			return err
+		}
+
+		var end time.Time
+		if logEnd != "" {
+			if end, err = utility.ParseTime(logEnd, now); err != nil {
+				return err
+			}
+		} else {
+			mins, err := parseMinutes(logDuration)
+			if err != nil {
+				return err
+			}
+			end = start.Add(time.Duration(mins) * time.Minute)
+		}
+
+		if !end.After(start) {
+			return fmt.Errorf("session must end after it starts (%s to %s)", formatLogTime(start), formatLogTime(end))
+		}
+		if end.After(now) {
+			return fmt.Errorf("session can't end in the future (%s)", formatLogTime(end))
+		}
+
+		sess := models.Session{GameName: args[0], StartTime: start, Source: models.SourceManual}
+		if game, ok := configuredGame(args[0]); ok {
+			sess.GameName = game.DisplayName()
+			sess.Class = game.Class
+		}
+		entry := session.Entry(sess, end)
+
+		description := fmt.Sprintf("Logged %s: %s to %s (%s)", entry.Game, formatLogTime(start), formatLogTime(end),
+			utility.FormatDurationExact(end.Sub(start)))
+
+		// Check for overlaps and add the entry under one lock, so nothing
+		// can be logged in between
+		err = session.Rewrite(cfg.Settings.SessionsFile, func(sessions []models.SessionLog) ([]models.SessionLog, error) {
+			if overlaps := session.Overlapping(sessions, entry); len(overlaps) > 0 {
+				o := overlaps[0]
+				oStart, _ := time.Parse(time.RFC3339, o.Start)
+				oEnd, _ := time.Parse(time.RFC3339, o.End)
+				return nil, fmt.Errorf("overlaps a logged %s session (%s to %s)", o.Game, formatLogTime(oStart), formatLogTime(oEnd))
+			}
+			return append(sessions, entry), nil
+		}, func() error {
+			recordChange("log add", description, nil, []models.SessionLog{entry})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(description)
+		return nil
+	},
+}
+
+func formatLogTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func init() {
+	logAddCmd.Flags().StringVar(&logStart, "start", "", "when the session started")
+	logAddCmd.Flags().StringVar(&logEnd, "end", "", "when the session ended")
+	logAddCmd.Flags().StringVar(&logDuration, "duration", "", "how long the session lasted, in minutes or as a duration such as 2h30m")
+	logCmd.AddCommand(logAddCmd)
+	rootCmd.AddCommand(logCmd)
+}
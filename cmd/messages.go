@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/baseline"
+	"github.com/austincgause/gametrak/internal/budget"
+	"github.com/austincgause/gametrak/internal/messages"
+	"github.com/austincgause/gametrak/internal/models"
+	"github.com/austincgause/gametrak/internal/notify"
+	"github.com/austincgause/gametrak/internal/session"
+	"github.com/austincgause/gametrak/internal/utility"
+)
+
+// sessionFields fills in the template fields describing a session
+func sessionFields(sess *models.Session) messages.Data {
+	return messages.Data{
+		Game:           sess.GameName,
+		Class:          sess.Class,
+		Title:          sess.Title,
+		Address:        sess.Address,
+		Start:          sess.StartTime,
+		MinSessionMins: cfg.Settings.MinSessionMins,
+	}
+}
+
+// sessionMessageData describes a session running until end, with today's
+// and all-time totals for its game and its previous longest session. The
+// all-time total includes imported baselines. The session itself is counted
+// in the totals, so it must not have been logged yet.
+func sessionMessageData(sess *models.Session, end time.Time) messages.Data {
+	data := sessionFields(sess)
+	data.End = end
+	data.Duration = end.Sub(sess.StartTime)
+
+	var logs []models.SessionLog
+	if h := gameHistories()[sess.GameName]; h != nil {
+		logs = h.logs
+		data.AllTimeTotal = h.total
+		data.LongestSession = h.longest
+	}
+	data.AllTimeTotal += data.Duration + importedTotal(sess.GameName)
+
+	data.TodayTotal = budget.Tally(logs, []models.Session{*sess}, utility.StartOfDay(end), end, allClasses)
+
+	return data
+}
+
+// importedTotal returns the playtime imported from other launchers for a
+// game
+func importedTotal(game string) time.Duration {
+	baselines, err := baseline.Load(cfg.Settings.BaselinesFile)
+	if err != nil {
+		slog.Warn("failed to load baselines for message totals", "error", err)
+		return 0
+	}
+	return time.Duration(baseline.Totals(baselines, "")[game]) * time.Second
+}
+
+// gameHistory is one game's logged sessions and totals
+type gameHistory struct {
+	logs    []models.SessionLog
+	total   time.Duration
+	longest time.Duration
+}
+
+// historyCache holds the per-game histories along with the size and
+// modification time of the sessions file they were read from
+var historyCache struct {
+	size    int64
+	modTime time.Time
+	games   map[string]*gameHistory
+}
+
+// gameHistories returns every game's history, reading the sessions file
+// only when it has changed since the last call rather than on every event
+func gameHistories() map[string]*gameHistory {
+	info, err := os.Stat(cfg.Settings.SessionsFile)
+	if os.IsNotExist(err) {
+		historyCache.games = nil
+		return nil
+	}
+	if err == nil && historyCache.games != nil &&
+		info.Size() == historyCache.size && info.ModTime().Equal(historyCache.modTime) {
+		return historyCache.games
+	}
+
+	history, err := session.LoadAll(cfg.Settings.SessionsFile)
+	if err != nil {
+		slog.Warn("failed to load sessions for message totals", "error", err)
+		return historyCache.games
+	}
+
+	games := make(map[string]*gameHistory)
+	for _, s := range history {
+		h := games[s.Game]
+		if h == nil {
+			h = &gameHistory{}
+			games[s.Game] = h
+		}
+		d := time.Duration(s.DurationSeconds) * time.Second
+		h.logs = append(h.logs, s)
+		h.total += d
+		h.longest = max(h.longest, d)
+	}
+
+	historyCache.games = games
+	if info != nil {
+		historyCache.size, historyCache.modTime = info.Size(), info.ModTime()
+	}
+	return games
+}
+
+// printMessage logs a rendered message's console line, if it has one
+func printMessage(msg messages.Rendered) {
+	if msg.Console != "" {
+		slog.Info(msg.Console)
+	}
+}
+
+// notifyMessage sends a rendered message as a notification when
+// notifications are enabled and the message has a title or body
+func notifyMessage(event, urgency string, msg messages.Rendered) {
+	if !cfg.Settings.Notifications || (msg.Title == "" && msg.Body == "") {
+		return
+	}
+	if err := notify.Send(event, msg.Title, msg.Body, urgency); err != nil {
+		slog.Debug("failed to send notification", "event", event, "error", err)
+	}
+}
+
+// reportError logs an error and sends an error notification. Errors are
+// always notified, even when other notifications are disabled.
+func reportError(message string) {
+	msg := messages.Render(messages.Error, messages.Data{Message: message})
+	if msg.Console != "" {
+		slog.Error(msg.Console)
+	}
+	emit(monitorEvent{Event: eventError, Message: message})
+	notify.Send(notify.EventError, msg.Title, msg.Body, notify.UrgencyCritical)
+}
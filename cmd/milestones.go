@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/messages"
+	"github.com/austincgause/gametrak/internal/notify"
+	"github.com/austincgause/gametrak/internal/utility"
+)
+
+// checkMilestones announces the milestones a just-logged session reached:
+// its game's all-time total passing a configured number of hours, and a new
+// longest session for the game. A game's first session is never a record.
+func checkMilestones(details messages.Data) {
+	milestones := cfg.Milestones
+
+	before := details.AllTimeTotal - details.Duration
+	hours := append([]int(nil), milestones.Hours...)
+	sort.Sort(sort.Reverse(sort.IntSlice(hours)))
+	for _, h := range hours {
+		threshold := time.Duration(h) * time.Hour
+		if h > 0 && before < threshold && details.AllTimeTotal >= threshold {
+			// Only the highest threshold crossed is worth announcing
+			data := details
+			data.Subject = "Milestone Reached"
+			data.Message = fmt.Sprintf("%s passed %d hours played", details.Game, h)
+			sendMilestone(data)
+			break
+		}
+	}
+
+	if milestones.LongestSession && details.LongestSession > 0 && details.Duration > details.LongestSession {
+		data := details
+		data.Subject = "New Longest Session"
+		data.Message = fmt.Sprintf("%s - %s (previous best %s)", details.Game,
+			utility.FormatDurationRounded(details.Duration), utility.FormatDurationRounded(details.LongestSession))
+		sendMilestone(data)
+	}
+}
+
+func sendMilestone(data messages.Data) {
+	msg := messages.Render(messages.Milestone, data)
+	printMessage(msg)
+	notifyMessage(notify.EventMilestone, notify.UrgencyNormal, msg)
+}
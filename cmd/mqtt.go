@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/budget"
+	"github.com/austincgause/gametrak/internal/config"
+	"github.com/austincgause/gametrak/internal/models"
+	"github.com/austincgause/gametrak/internal/mqtt"
+	"github.com/austincgause/gametrak/internal/session"
+	"github.com/austincgause/gametrak/internal/utility"
+)
+
+var mqttPublisher *mqtt.Publisher
+
+// startMQTT connects to the configured broker in the background and
+// publishes the initial state
+func startMQTT() {
+	m := cfg.MQTT
+
+	broker := m.Broker
+	if broker == "" {
+		broker = config.DefaultMQTTBroker
+	}
+	topic := m.Topic
+	if topic == "" {
+		topic = config.DefaultMQTTTopic
+	}
+	discoveryPrefix := m.DiscoveryPrefix
+	if discoveryPrefix == "" {
+		discoveryPrefix = config.DefaultDiscoveryPrefix
+	}
+	if m.DisableDiscovery {
+		discoveryPrefix = ""
+	}
+	clientID := m.ClientID
+	if clientID == "" {
+		host, _ := os.Hostname()
+		clientID = "gametrak-" + host
+	}
+
+	mqttPublisher = mqtt.NewPublisher(mqtt.PublisherOptions{
+		Options: mqtt.Options{
+			Broker:   broker,
+			ClientID: clientID,
+			Username: m.Username,
+			Password: m.Password,
+			Logf: func(format string, args ...any) {
+				slog.Warn(fmt.Sprintf(format, args...))
+			},
+		},
+		BaseTopic:       topic,
+		DiscoveryPrefix: discoveryPrefix,
+	})
+
+	slog.Info("publishing to MQTT broker", "broker", broker, "topic", topic)
+	publishMQTTState()
+}
+
+// publishMQTTState sends the retained state with today's totals, counting
+// logged sessions and the ones still running
+func publishMQTTState() {
+	if mqttPublisher == nil {
+		return
+	}
+
+	logs, err := session.LoadAll(cfg.Settings.SessionsFile)
+	if err != nil {
+		slog.Warn("failed to load sessions for MQTT state", "error", err)
+	}
+
+	now := time.Now()
+	startOfDay := utility.StartOfDay(now)
+	active := activeSessionList()
+
+	st := mqtt.State{TodayByGame: make(map[string]int64), DayStart: startOfDay.Format(time.RFC3339)}
+	var since time.Time
+	for _, s := range logs {
+		if d := budget.Tally([]models.SessionLog{s}, nil, startOfDay, now, allClasses); d > 0 {
+			st.TodayByGame[s.Game] += int64(d.Seconds())
+		}
+	}
+	for _, s := range active {
+		st.TodayByGame[s.GameName] += int64(budget.Tally(nil, []models.Session{s}, startOfDay, now, allClasses).Seconds())
+
+		// Report the longest-running game as the current one
+		if !st.Playing || s.StartTime.Before(since) {
+			st.Playing = true
+			st.Game = s.GameName
+			since = s.StartTime
+		}
+	}
+	if st.Playing {
+		st.Since = since.Format(time.RFC3339)
+	}
+	for _, secs := range st.TodayByGame {
+		st.TodaySeconds += secs
+	}
+
+	mqttPublisher.PublishState(st)
+}
+
+func mqttSessionEvent(sess *models.Session) mqtt.SessionEvent {
+	return mqtt.SessionEvent{
+		Game:  sess.GameName,
+		Class: sess.Class,
+		Title: sess.Title,
+		Start: sess.StartTime.Format(time.RFC3339),
+	}
+}
+
+func allClasses(string) bool { return true }
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/models"
+	"github.com/austincgause/gametrak/internal/notify"
+	"github.com/austincgause/gametrak/internal/utility"
+)
+
+// Output formats for the monitor
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// Lifecycle events emitted with --output json
+const (
+	eventConnected      = "connected"
+	eventGameStarted    = "game_started"
+	eventTitleChanged   = "title_changed"
+	eventGameEnded      = "game_ended"
+	eventSessionLogged  = "session_logged"
+	eventSessionSkipped = "session_skipped"
+	eventError          = "error"
+)
+
+var (
+	outputFormat = outputText
+
+	// console receives the monitor's log output. With JSON output it
+	// moves to stderr so stdout carries only events.
+	console io.Writer = os.Stdout
+)
+
+// monitorEvent is one line of the JSON event stream. Field names are part
+// of the output format and must not change.
+type monitorEvent struct {
+	Event           string `json:"event"`
+	Time            string `json:"time"`
+	Game            string `json:"game,omitempty"`
+	Class           string `json:"class,omitempty"`
+	Title           string `json:"title,omitempty"`
+	PreviousTitle   string `json:"previous_title,omitempty"`
+	Address         string `json:"address,omitempty"`
+	Start           string `json:"start,omitempty"`
+	End             string `json:"end,omitempty"`
+	DurationSeconds *int64 `json:"duration_seconds,omitempty"`
+	Reason          string `json:"reason,omitempty"`
+	Socket          string `json:"socket,omitempty"`
+	Message         string `json:"message,omitempty"`
+}
+
+// setOutput selects the monitor's output format
+func setOutput(format string) error {
+	switch format {
+	case outputText:
+		console = os.Stdout
+	case outputJSON:
+		console = os.Stderr
+		notify.SetConsole(os.Stderr)
+	default:
+		return fmt.Errorf("invalid output format %q (expected text or json)", format)
+	}
+	outputFormat = format
+	return nil
+}
+
+// sessionEvent describes a session for the event stream. A zero end leaves
+// the end time and duration out.
+func sessionEvent(event string, sess *models.Session, end time.Time) monitorEvent {
+	ev := monitorEvent{
+		Event:   event,
+		Game:    sess.GameName,
+		Class:   sess.Class,
+		Title:   sess.Title,
+		Address: sess.Address,
+		Start:   sess.StartTime.Format(time.RFC3339),
+	}
+	if !end.IsZero() {
+		secs := int64(end.Sub(sess.StartTime).Seconds())
+		ev.End = end.Format(time.RFC3339)
+		ev.DurationSeconds = &secs
+	}
+	return ev
+}
+
+// emit sends an event to watchers on the control socket, and writes it to
+// stdout when JSON output is selected
+func emit(ev monitorEvent) {
+	if ev.Time == "" {
+		ev.Time = time.Now().Format(time.RFC3339)
+	}
+	controlServer.Broadcast(ev)
+
+	if outputFormat != outputJSON {
+		return
+	}
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	os.Stdout.Write(append(data, '\n'))
+}
+
+// formatEvent describes an event as a line of human-readable output
+func formatEvent(ev monitorEvent) string {
+	ts := ev.Time
+	if t, err := time.Parse(time.RFC3339, ev.Time); err == nil {
+		ts = t.Local().Format("15:04:05")
+	}
+
+	var text string
+	switch ev.Event {
+	case eventConnected:
+		text = "Connected to Hyprland"
+	case eventGameStarted:
+		text = "Game started: " + ev.Game
+	case eventTitleChanged:
+		text = fmt.Sprintf("Title changed: %s (%s)", ev.Title, ev.Game)
+	case eventGameEnded:
+		text = "Game ended: " + ev.Game
+		if ev.DurationSeconds != nil {
+			text += " - Session: " + utility.FormatDurationExact(time.Duration(*ev.DurationSeconds)*time.Second)
+		}
+	case eventSessionLogged:
+		text = "Session logged: " + ev.Game
+	case eventSessionSkipped:
+		text = fmt.Sprintf("Session not logged: %s (%s)", ev.Game, strings.ReplaceAll(ev.Reason, "_", " "))
+	case eventError:
+		text = "Error: " + ev.Message
+	default:
+		text = ev.Event
+	}
+
+	return fmt.Sprintf("[%s] %s", ts, text)
+}
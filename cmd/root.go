@@ -2,28 +2,61 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/austincgause/gametrak/internal/config"
+	"github.com/austincgause/gametrak/internal/control"
+	"github.com/austincgause/gametrak/internal/gamemode"
+	"github.com/austincgause/gametrak/internal/gamescope"
 	"github.com/austincgause/gametrak/internal/hyprland"
+	"github.com/austincgause/gametrak/internal/launchers"
+	"github.com/austincgause/gametrak/internal/logging"
+	"github.com/austincgause/gametrak/internal/messages"
+	"github.com/austincgause/gametrak/internal/metrics"
 	"github.com/austincgause/gametrak/internal/models"
 	"github.com/austincgause/gametrak/internal/notify"
 	"github.com/austincgause/gametrak/internal/session"
+	"github.com/austincgause/gametrak/internal/state"
+	"github.com/austincgause/gametrak/internal/steam"
+	"github.com/austincgause/gametrak/internal/systemd"
 	"github.com/austincgause/gametrak/internal/utility"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+const (
+	// checkInterval is how often the monitor re-evaluates budgets and
+	// schedules while games are running
+	checkInterval = time.Minute
+
+	// reconnectAttempts and reconnectDelay bound how long the monitor waits
+	// for the Hyprland socket to come back; the delay doubles each attempt
+	reconnectAttempts = 5
+	reconnectDelay    = time.Second
+)
+
 var (
 	cfg             models.Config
 	cfgFile         string
 	debugMode       bool
+	logLevel        string
+	logFormat       string
+	logFile         string
+	logToFile       bool
 	activeSessions  = make(map[string]*models.Session)
 	shutdownRequest bool
+	monitorMetrics  *metrics.Metrics
+	controlServer   *control.Server
+	steamResolver   *steam.Resolver
+	launcherIndex   *launchers.Index
 )
 
 var rootCmd = &cobra.Command{
@@ -42,10 +75,31 @@ Running gametrak without subcommands starts the monitoring service.`,
 		if cmd.Name() == "help" {
 			return nil
 		}
-		return config.Load(&cfg)
+		if err := config.Load(&cfg); err != nil {
+			return err
+		}
+		if err := notify.Configure(cfg.Notifiers); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		if err := messages.Configure(cfg.Messages); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		return nil
 	},
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setOutput(outputFormat); err != nil {
+			return err
+		}
+		closeLog, err := setupLogging()
+		if err != nil {
+			return err
+		}
+		defer closeLog.Close()
+		defer notify.Flush()
+		defer flushActivityWatch()
+
 		runMonitor()
+		return nil
 	},
 }
 
@@ -60,7 +114,12 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $XDG_CONFIG_HOME/gametrak/config.yaml)")
-	rootCmd.Flags().BoolVarP(&debugMode, "debug", "d", false, "print all Hyprland events for debugging")
+	rootCmd.Flags().BoolVarP(&debugMode, "debug", "d", false, "log all Hyprland events (same as --log-level debug)")
+	rootCmd.Flags().StringVar(&logLevel, "log-level", "info", "log level: debug, info, warn or error")
+	rootCmd.Flags().StringVar(&logFormat, "log-format", logging.FormatText, "log format: text or json")
+	rootCmd.Flags().BoolVar(&logToFile, "log-to-file", false, "also log to a rotating file at "+config.DefaultLogFile)
+	rootCmd.Flags().StringVar(&logFile, "log-file", "", "also log to a rotating file at this path")
+	rootCmd.Flags().StringVarP(&outputFormat, "output", "o", outputText, "output format: text or json (one event object per line)")
 }
 
 func initConfig() {
@@ -80,32 +139,70 @@ func initConfig() {
 		fmt.Fprintf(os.Stderr, "Warning: failed to create default config: %v\n", err)
 	}
 
-	if err := viper.ReadInConfig(); err == nil {
-		if debugMode {
-			fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
-		}
+	viper.ReadInConfig()
+}
+
+// setupLogging installs the monitor's logger. --debug is shorthand for the
+// debug level, and --log-to-file for --log-file with the default path.
+func setupLogging() (io.Closer, error) {
+	level := logLevel
+	if debugMode {
+		level = "debug"
+	}
+	file := logFile
+	if logToFile && file == "" {
+		file = config.DefaultLogFile
 	}
+	return logging.Setup(logging.Options{
+		Level:   level,
+		Format:  logFormat,
+		Console: console,
+		File:    file,
+	})
 }
 
 func runMonitor() {
 	socketPath, err := hyprland.GetSocketPath()
 	if err != nil {
-		notify.Error(err.Error())
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		reportError(err.Error())
+		notify.Flush()
 		os.Exit(1)
 	}
 
-	fmt.Printf("[%s] Connecting to Hyprland socket: %s\n", utility.Timestamp(), socketPath)
+	slog.Debug("using config file", "path", viper.ConfigFileUsed())
+	slog.Info("connecting to Hyprland socket", "socket", socketPath)
 
 	conn, err := hyprland.Connect()
 	if err != nil {
-		notify.Error(err.Error())
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		reportError(err.Error())
+		notify.Flush()
 		os.Exit(1)
 	}
-	defer conn.Close()
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+
+	slog.Info("connected, listening for game events")
+
+	if server, err := control.Listen(config.DefaultControl); err != nil {
+		slog.Warn("status and watch unavailable", "error", err)
+	} else {
+		controlServer = server
+		defer controlServer.Close()
+	}
 
-	fmt.Printf("[%s] Connected. Listening for game events...\n", utility.Timestamp())
+	if cfg.Metrics.Enabled {
+		startMetrics()
+	}
+	if cfg.ActivityWatch.Enabled {
+		startActivityWatch()
+	}
+	if cfg.MQTT.Enabled {
+		startMQTT()
+		defer mqttPublisher.Close()
+	}
 
 	// Send startup notification
 	if cfg.Settings.Notifications {
@@ -117,7 +214,7 @@ func runMonitor() {
 	for _, g := range cfg.Games {
 		gameNames = append(gameNames, g.DisplayName())
 	}
-	fmt.Printf("[%s] Watching for: %v\n", utility.Timestamp(), gameNames)
+	slog.Info("watching for games", "games", gameNames)
 
 	// Set up channels for events
 	events := make(chan string)
@@ -129,46 +226,239 @@ func runMonitor() {
 
 	go hyprland.Listen(conn, events, errors)
 
+	publishState()
+	defer state.Remove(config.DefaultStateFile)
+	emit(monitorEvent{Event: eventConnected, Socket: socketPath})
+	systemd.Ready(serviceStatus())
+	defer systemd.Stopping()
+
+	var gameModeEvents <-chan gamemode.Event
+	if cfg.GameMode.Enabled {
+		var watcher *gamemode.Watcher
+		if watcher, gameModeEvents = startGameMode(); watcher != nil {
+			defer watcher.Close()
+		}
+	}
+
+	// Keep systemd's watchdog fed from the event loop, so a hung loop gets
+	// the service restarted
+	var watchdog <-chan time.Time
+	if interval, ok := systemd.WatchdogInterval(); ok {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		watchdog = ticker.C
+	}
+
+	steamResolver = steam.NewResolver(cfg.Settings.SteamDir, config.DefaultSteamCache)
+	launcherIndex = launchers.NewIndex()
+
+	// Periodically re-check budgets and schedules while games run
+	loadSchedules()
+	checkTicker := time.NewTicker(checkInterval)
+	defer checkTicker.Stop()
+	seedBudgetWarnings()
+	checkBudgets()
+
 	for {
 		select {
 		case <-sigChan:
 			shutdownRequest = true
-			fmt.Printf("\n[%s] Shutting down...\n", utility.Timestamp())
+			slog.Info("shutting down")
 			conn.Close()
 			return
 
 		case err := <-errors:
-			if !shutdownRequest {
-				notify.Error(fmt.Sprintf("Socket error: %v", err))
-				fmt.Fprintf(os.Stderr, "Error reading from socket: %v\n", err)
-				os.Exit(1)
+			if shutdownRequest {
+				return
+			}
+			slog.Error("error reading from socket", "error", err)
+			if conn, events, errors = reconnect(conn, err, sigChan); conn == nil {
+				return
 			}
-			return
 
 		case line, ok := <-events:
 			if !ok {
-				return
+				if shutdownRequest {
+					return
+				}
+				slog.Warn("Hyprland socket closed")
+				if conn, events, errors = reconnect(conn, fmt.Errorf("connection closed"), sigChan); conn == nil {
+					return
+				}
+				continue
 			}
 			handleEvent(line)
+
+		case ev, ok := <-gameModeEvents:
+			if !ok {
+				slog.Warn("lost connection to GameMode")
+				gameModeEvents = nil
+				continue
+			}
+			handleGameMode(ev)
+
+		case req := <-controlServer.Sessions():
+			handleRunRequest(req)
+
+		case <-watchdog:
+			systemd.Watchdog()
+
+		case <-checkTicker.C:
+			if len(activeSessions) > 0 {
+				checkBudgets()
+				checkSchedules()
+				publishMQTTState()
+			}
 		}
 	}
 }
 
-func handleEvent(line string) {
-	if debugMode {
-		fmt.Printf("[%s] DEBUG: %s\n", utility.Timestamp(), line)
+// reconnect re-establishes the event socket after it drops, retrying with
+// exponential backoff. It exits the process once the retries are exhausted,
+// and returns a nil connection if a shutdown signal arrives while waiting.
+func reconnect(old net.Conn, cause error, sigChan <-chan os.Signal) (net.Conn, chan string, chan error) {
+	old.Close()
+
+	delay := reconnectDelay
+	for attempt := 1; attempt <= reconnectAttempts; attempt++ {
+		slog.Info("reconnecting to Hyprland socket", "delay", delay, "attempt", attempt, "max_attempts", reconnectAttempts)
+		systemd.Status(fmt.Sprintf("Reconnecting to Hyprland (attempt %d/%d)", attempt, reconnectAttempts))
+		systemd.Watchdog()
+
+		select {
+		case <-sigChan:
+			shutdownRequest = true
+			slog.Info("shutting down")
+			return nil, nil, nil
+		case <-time.After(delay):
+		}
+
+		conn, err := hyprland.Connect()
+		if err != nil {
+			cause = err
+			delay *= 2
+			continue
+		}
+
+		monitorMetrics.Reconnect()
+		slog.Info("reconnected, listening for game events")
+		endClosedSessions()
+		systemd.Status(serviceStatus())
+		emit(monitorEvent{Event: eventConnected, Socket: conn.RemoteAddr().String()})
+
+		events := make(chan string)
+		errors := make(chan error)
+		go hyprland.Listen(conn, events, errors)
+		return conn, events, errors
+	}
+
+	reportError(fmt.Sprintf("could not reconnect to Hyprland socket: %v", cause))
+	endAllSessions()
+	flushActivityWatch()
+	notify.Flush()
+	os.Exit(1)
+	return nil, nil, nil
+}
+
+// endClosedSessions ends the sessions whose windows closed while the event
+// socket was down, since their closewindow events were missed. Sessions
+// from GameMode and gametrak run don't depend on the socket and are kept.
+func endClosedSessions() {
+	clients, err := hyprland.Clients()
+	if err != nil {
+		slog.Warn("failed to list windows after reconnecting", "error", err)
+		return
+	}
+
+	open := make(map[string]bool, len(clients))
+	for _, c := range clients {
+		open[strings.TrimPrefix(c.Address, "0x")] = true
+	}
+	for address, sess := range activeSessions {
+		if _, pseudo := sessionPID(address); pseudo || open[strings.TrimPrefix(address, "0x")] {
+			continue
+		}
+		slog.Info("game window closed while disconnected", "game", sess.GameName)
+		endSession(address)
+	}
+}
+
+// endAllSessions ends every session before the monitor gives up, so they are
+// logged rather than lost. Sessions from gametrak run are logged by it.
+func endAllSessions() {
+	for address := range activeSessions {
+		if !strings.HasPrefix(address, runPrefix) {
+			endSession(address)
+		}
+	}
+}
+
+// startMetrics serves the Prometheus endpoint, seeding the per-game totals
+// from the session history. Failures are reported but not fatal.
+func startMetrics() {
+	history, err := session.LoadAll(cfg.Settings.SessionsFile)
+	if err != nil {
+		slog.Warn("failed to load sessions for metrics", "error", err)
+	}
+
+	addr := cfg.Metrics.Listen
+	if addr == "" {
+		addr = config.DefaultMetricsListen
+	}
+
+	m := metrics.New(history)
+	if _, err := m.Serve(addr); err != nil {
+		slog.Warn("metrics disabled", "error", err)
+		return
+	}
+
+	monitorMetrics = m
+	slog.Info("serving metrics", "url", "http://"+addr+"/metrics")
+}
+
+// publishState writes the active sessions to the state file and control
+// socket so other commands can see what is being played
+func publishState() {
+	controlServer.SetStatus(state.New(activeSessions))
+	systemd.Status(serviceStatus())
+	if err := state.Write(config.DefaultStateFile, activeSessions); err != nil {
+		slog.Warn("failed to write state file", "error", err)
+	}
+}
+
+// serviceStatus describes what the monitor is doing for systemctl status
+func serviceStatus() string {
+	snap := state.New(activeSessions)
+	switch len(snap.Sessions) {
+	case 0:
+		return "Waiting for a game"
+	case 1:
+		return "Playing " + snap.Sessions[0].Game
+	default:
+		names := make([]string, len(snap.Sessions))
+		for i, s := range snap.Sessions {
+			names[i] = s.Game
+		}
+		return "Playing " + strings.Join(names, ", ")
 	}
+}
+
+func handleEvent(line string) {
+	slog.Debug("hyprland event", "line", line)
 
 	eventType, data, ok := hyprland.ParseEvent(line)
 	if !ok {
 		return
 	}
+	monitorMetrics.Event(eventType)
 
 	switch eventType {
 	case hyprland.EventOpenWindow:
 		handleOpenWindow(data)
 	case hyprland.EventCloseWindow:
 		handleCloseWindow(data)
+	case hyprland.EventWindowTitle:
+		handleWindowTitle(data)
 	}
 }
 
@@ -178,30 +468,75 @@ func handleOpenWindow(data string) {
 		return
 	}
 
+	if _, exists := activeSessions[event.Address]; exists {
+		return
+	}
+
+	// Games in gamescope all share its window class, so identify the game
+	// from gamescope's processes instead
+	if gamescope.IsGamescope(event.Class) {
+		if class, ok := resolveGamescope(event.Address); ok {
+			event.Class = class
+		}
+	}
+
 	game, matched := utility.MatchGame(event.Class, cfg.Games)
 	if !matched {
 		return
 	}
 
-	// Determine the game name for logging/history
-	gameName := game.DisplayName()
-	if game.UseTitle && event.Title != "" {
-		gameName = utility.SanitizeTitle(event.Title)
+	// GameMode or gametrak run may already be tracking this game
+	if trackedByGameMode(event.Class) || trackedByRun(event.Address, event.Class) {
+		return
 	}
 
+	startSession(event.Address, event.Class, event.Title, game)
+}
+
+// startSession begins tracking a game. Address identifies the session: a
+// window address, or a pseudo-address for sessions without a window.
+func startSession(address, class, title string, game models.Game) {
+	gameName := sessionName(class, title, game)
+
 	sess := &models.Session{
-		Address:   event.Address,
-		Class:     event.Class,
-		Title:     event.Title,
+		Address:   address,
+		Class:     class,
+		Title:     title,
 		GameName:  gameName,
 		StartTime: time.Now(),
 	}
-	activeSessions[event.Address] = sess
-
-	displayName := gameName
+	activeSessions[address] = sess
+	monitorMetrics.SessionStarted(gameName)
+	publishState()
+	mqttPublisher.SessionStarted(mqttSessionEvent(sess))
+	publishMQTTState()
+
+	emit(sessionEvent(eventGameStarted, sess, time.Time{}))
+	msg := messages.Render(messages.Start, sessionMessageData(sess, sess.StartTime))
+	printMessage(msg)
+	notifyMessage(notify.EventGameStarted, notify.UrgencyLow, msg)
+
+	checkSchedule(address, sess, sess.StartTime)
+	checkBudgets()
+}
 
-	fmt.Printf("[%s] Game started: %s (class: %s, address: %s)\n",
-		utility.Timestamp(), displayName, event.Class, event.Address)
+// sessionName determines the game name for logging/history. Games from
+// Steam and other launchers are named from their libraries, since window
+// titles are often unhelpful.
+func sessionName(class, title string, game models.Game) string {
+	if !game.UseTitle {
+		return game.DisplayName()
+	}
+	if name, ok := steamResolver.Name(class); ok {
+		return name
+	}
+	if name, ok := launcherIndex.Name(class); ok {
+		return name
+	}
+	if title != "" {
+		return utility.SanitizeTitle(title)
+	}
+	return game.DisplayName()
 }
 
 func handleCloseWindow(data string) {
@@ -210,40 +545,86 @@ func handleCloseWindow(data string) {
 		return
 	}
 
-	sess, exists := activeSessions[event.Address]
+	endSession(event.Address)
+}
+
+// endSession stops tracking the session at address, logging it if it ran
+// long enough
+func endSession(address string) {
+	sess, exists := activeSessions[address]
 	if !exists {
 		return
 	}
 
 	endTime := time.Now()
 	duration := endTime.Sub(sess.StartTime)
-	delete(activeSessions, event.Address)
+	delete(activeSessions, address)
+	delete(scheduleFlagged, address)
+	monitorMetrics.SessionEnded(sess.GameName)
+	publishState()
 
-	displayName := sess.Title
-	if displayName == "" {
-		displayName = sess.GameName
-	}
-
-	fmt.Printf("[%s] Game ended: %s - Session: %s\n",
-		utility.Timestamp(), displayName, utility.FormatDurationExact(duration))
+	emit(sessionEvent(eventGameEnded, sess, endTime))
+	details := sessionMessageData(sess, endTime)
+	msg := messages.Render(messages.End, details)
+	printMessage(msg)
 
 	// Log session if enabled and meets minimum duration
 	minDuration := time.Duration(cfg.Settings.MinSessionMins) * time.Minute
+	logged := false
 	if cfg.Settings.LogSessions && duration >= minDuration {
 		if err := session.Log(cfg.Settings.SessionsFile, *sess, endTime); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to log session: %v\n", err)
+			monitorMetrics.LogWriteFailure()
+			slog.Warn("failed to log session", "error", err)
+			emit(monitorEvent{Event: eventError, Message: fmt.Sprintf("failed to log session: %v", err)})
+		} else {
+			monitorMetrics.SessionLogged(sess.GameName, int64(duration.Seconds()))
+			emit(sessionEvent(eventSessionLogged, sess, endTime))
+			exportSession(session.Entry(*sess, endTime))
+			logged = true
 		}
 	} else if cfg.Settings.LogSessions && duration < minDuration {
-		fmt.Printf("[%s] Session too short to log (min: %d mins)\n",
-			utility.Timestamp(), cfg.Settings.MinSessionMins)
+		printMessage(messages.Render(messages.TooShort, details))
+		ev := sessionEvent(eventSessionSkipped, sess, endTime)
+		ev.Reason = "too_short"
+		emit(ev)
+	} else {
+		ev := sessionEvent(eventSessionSkipped, sess, endTime)
+		ev.Reason = "logging_disabled"
+		emit(ev)
 	}
 
-	// Send notification if enabled
-	if cfg.Settings.Notifications {
-		if err := notify.GameEnded(displayName, duration); err != nil {
-			if debugMode {
-				fmt.Fprintf(os.Stderr, "Warning: failed to send notification: %v\n", err)
-			}
-		}
+	ev := mqttSessionEvent(sess)
+	ev.End = endTime.Format(time.RFC3339)
+	ev.DurationSeconds = int64(duration.Seconds())
+	mqttPublisher.SessionEnded(ev)
+	publishMQTTState()
+
+	notifyMessage(notify.EventGameEnded, notify.UrgencyNormal, msg)
+	if logged {
+		checkMilestones(details)
 	}
+
+	checkBudgets()
+}
+
+// handleWindowTitle keeps an active session's title current. The game name
+// chosen when the session started is kept.
+func handleWindowTitle(data string) {
+	event, ok := hyprland.ParseWindowTitle(data)
+	if !ok {
+		return
+	}
+
+	sess, exists := activeSessions[event.Address]
+	if !exists || sess.Title == event.Title {
+		return
+	}
+
+	previous := sess.Title
+	sess.Title = event.Title
+	publishState()
+
+	ev := sessionEvent(eventTitleChanged, sess, time.Time{})
+	ev.PreviousTitle = previous
+	emit(ev)
 }
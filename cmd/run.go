@@ -0,0 +1,367 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/config"
+	"github.com/austincgause/gametrak/internal/control"
+	"github.com/austincgause/gametrak/internal/hyprland"
+	"github.com/austincgause/gametrak/internal/launchers"
+	"github.com/austincgause/gametrak/internal/messages"
+	"github.com/austincgause/gametrak/internal/models"
+	"github.com/austincgause/gametrak/internal/notify"
+	"github.com/austincgause/gametrak/internal/process"
+	"github.com/austincgause/gametrak/internal/session"
+	"github.com/austincgause/gametrak/internal/state"
+	"github.com/austincgause/gametrak/internal/steam"
+	"github.com/austincgause/gametrak/internal/utility"
+	"github.com/spf13/cobra"
+	"golang.org/x/sys/unix"
+)
+
+// runPrefix marks the pseudo-addresses of sessions recorded by gametrak
+// run, which are keyed by the wrapped process
+const runPrefix = "run:"
+
+var (
+	runName  string
+	runClass string
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run [flags] -- <command> [args...]",
+	Short: "Run a game and record a session for as long as it runs",
+	Long: `Launch a game and record a session covering exactly its lifetime,
+including any processes it leaves behind.
+
+This works without Hyprland or the monitor, which makes it suitable as a
+Steam or Lutris launch wrapper. If the monitor is running it is told about
+the session, so the game's window isn't counted twice.
+
+The game is identified by --class, by the Steam app being launched, or by
+the command's executable, in that order. It is named by --name, by the
+Steam and launcher libraries, or by a configured game with that class.
+
+Examples:
+  gametrak run --name "Elden Ring" -- %command%
+  gametrak run --class factorio -- ~/games/factorio/bin/x64/factorio`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// The game owns stdout; gametrak's own output goes to stderr
+		console = os.Stderr
+		notify.SetConsole(os.Stderr)
+		closeLog, err := setupLogging()
+		if err != nil {
+			return err
+		}
+
+		code, err := runGame(args)
+		notify.Flush()
+		closeLog.Close()
+		if err != nil {
+			return err
+		}
+		os.Exit(code)
+		return nil
+	},
+}
+
+// runGame runs the command as a tracked session and returns its exit code
+func runGame(args []string) (int, error) {
+	// Become a subreaper so processes the game leaves behind are reparented
+	// to us instead of init, and can be waited for
+	if err := unix.Prctl(unix.PR_SET_CHILD_SUBREAPER, 1, 0, 0, 0); err != nil {
+		slog.Warn("failed to become subreaper, only the launched process will be tracked", "error", err)
+	}
+
+	steamResolver = steam.NewResolver(cfg.Settings.SteamDir, config.DefaultSteamCache)
+	launcherIndex = launchers.NewIndex()
+
+	class := runGameClass(args[0])
+	game, matched := utility.MatchGame(class, cfg.Games)
+	name := runName
+	if name == "" {
+		if matched {
+			name = sessionName(class, "", game)
+		} else {
+			name = discoveredName(class)
+		}
+	}
+
+	// Signals for the game are forwarded rather than ending gametrak, so
+	// the session is still recorded when it exits
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(sigChan)
+
+	child := exec.Command(args[0], args[1:]...)
+	child.Stdin = os.Stdin
+	child.Stdout = os.Stdout
+	child.Stderr = os.Stderr
+	if err := child.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start %s: %w", args[0], err)
+	}
+
+	sess := &models.Session{
+		Address:   fmt.Sprintf("%s%d", runPrefix, child.Process.Pid),
+		Class:     class,
+		GameName:  name,
+		StartTime: time.Now(),
+		Source:    models.SourceRun,
+	}
+	sendRunSession(control.CommandSessionStart, sess)
+
+	msg := messages.Render(messages.Start, sessionMessageData(sess, sess.StartTime))
+	printMessage(msg)
+	notifyMessage(notify.EventGameStarted, notify.UrgencyLow, msg)
+
+	done := make(chan struct{})
+	go forwardSignals(sigChan, child.Process.Pid, done)
+
+	waitErr := child.Wait()
+	waitForDescendants()
+	close(done)
+
+	endRunSession(sess, time.Now())
+	sendRunSession(control.CommandSessionEnd, sess)
+
+	var exitErr *exec.ExitError
+	switch {
+	case waitErr == nil:
+		return 0, nil
+	case errors.As(waitErr, &exitErr):
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			return 128 + int(status.Signal()), nil
+		}
+		return exitErr.ExitCode(), nil
+	default:
+		return 0, waitErr
+	}
+}
+
+// runGameClass identifies the game being run. Steam sets SteamAppId for
+// the games it launches, which matches the class of their windows.
+func runGameClass(command string) string {
+	if runClass != "" {
+		return runClass
+	}
+	if id := os.Getenv("SteamAppId"); id != "" && id != "0" {
+		return steam.AppClassPrefix + id
+	}
+	return filepath.Base(command)
+}
+
+// forwardSignals passes signals on to the game while it runs, and to
+// whatever it left behind once it has exited
+func forwardSignals(sigChan <-chan os.Signal, pid int, done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case sig := <-sigChan:
+			if err := syscall.Kill(pid, sig.(syscall.Signal)); err == nil {
+				continue
+			}
+			for _, p := range process.Descendants(os.Getpid()) {
+				syscall.Kill(p, sig.(syscall.Signal))
+			}
+		}
+	}
+}
+
+// waitForDescendants waits for the processes the game left behind, such as
+// the real game started by a launcher that exited, to finish. Only those
+// still in the game's process group count: daemons that detached from it,
+// such as wineserver or a Steam client started by the game, can outlive it
+// by hours and aren't part of the session.
+func waitForDescendants() {
+	group := syscall.Getpgrp()
+	var waitingOn string
+	for {
+		reapChildren()
+
+		var remaining []string
+		for _, pid := range process.Descendants(os.Getpid()) {
+			if pgid, ok := process.Group(pid); ok && pgid == group {
+				remaining = append(remaining, processLabel(pid))
+			}
+		}
+		if len(remaining) == 0 {
+			return
+		}
+
+		if list := strings.Join(remaining, ", "); list != waitingOn {
+			slog.Info("waiting for game processes to exit", "processes", list)
+			waitingOn = list
+		}
+		time.Sleep(descendantPoll)
+	}
+}
+
+// descendantPoll is how often waitForDescendants checks on the game's
+// remaining processes
+const descendantPoll = 500 * time.Millisecond
+
+// reapChildren collects every exited child without blocking. As a subreaper
+// this includes the game's orphaned processes.
+func reapChildren() {
+	for {
+		var status syscall.WaitStatus
+		pid, err := syscall.Wait4(-1, &status, syscall.WNOHANG, nil)
+		if err == syscall.EINTR {
+			continue
+		}
+		if err != nil || pid <= 0 {
+			return
+		}
+	}
+}
+
+// processLabel names a process for logging as name[pid]
+func processLabel(pid int) string {
+	name := "?"
+	if exes := process.Executables(pid); len(exes) > 0 {
+		name = exes[0]
+	}
+	return fmt.Sprintf("%s[%d]", name, pid)
+}
+
+// endRunSession logs a finished session, following the same notification
+// and minimum duration settings as the monitor
+func endRunSession(sess *models.Session, endTime time.Time) {
+	duration := endTime.Sub(sess.StartTime)
+	details := sessionMessageData(sess, endTime)
+	msg := messages.Render(messages.End, details)
+	printMessage(msg)
+
+	minDuration := time.Duration(cfg.Settings.MinSessionMins) * time.Minute
+	logged := false
+	if cfg.Settings.LogSessions && duration >= minDuration {
+		if err := session.Log(cfg.Settings.SessionsFile, *sess, endTime); err != nil {
+			slog.Warn("failed to log session", "error", err)
+		} else {
+			logged = true
+		}
+	} else if cfg.Settings.LogSessions {
+		printMessage(messages.Render(messages.TooShort, details))
+	}
+
+	notifyMessage(notify.EventGameEnded, notify.UrgencyNormal, msg)
+	if logged {
+		checkMilestones(details)
+	}
+}
+
+// sendRunSession tells the running monitor, if any, about the session
+func sendRunSession(command string, sess *models.Session) {
+	snap := state.New(map[string]*models.Session{sess.Address: sess})
+	err := control.Send(config.DefaultControl, control.Request{Command: command, Session: &snap.Sessions[0]})
+	if err != nil && err != control.ErrNotRunning {
+		slog.Warn("failed to notify the monitor", "error", err)
+	}
+}
+
+// handleRunRequest applies a session reported by gametrak run. The monitor
+// shows it and enforces budgets and schedules on it, but leaves logging
+// and game notifications to gametrak run.
+func handleRunRequest(req control.Request) {
+	reported := req.Session
+	if !strings.HasPrefix(reported.Address, runPrefix) {
+		slog.Warn("ignoring session with unexpected address", "address", reported.Address)
+		return
+	}
+
+	switch req.Command {
+	case control.CommandSessionStart:
+		if _, exists := activeSessions[reported.Address]; exists {
+			return
+		}
+		start, err := time.Parse(time.RFC3339, reported.Start)
+		if err != nil {
+			start = time.Now()
+		}
+
+		sess := &models.Session{
+			Address:   reported.Address,
+			Class:     reported.Class,
+			Title:     reported.Title,
+			GameName:  reported.Game,
+			StartTime: start,
+		}
+		activeSessions[sess.Address] = sess
+		monitorMetrics.SessionStarted(sess.GameName)
+		publishState()
+		mqttPublisher.SessionStarted(mqttSessionEvent(sess))
+		publishMQTTState()
+		emit(sessionEvent(eventGameStarted, sess, time.Time{}))
+		slog.Info("game started by gametrak run", "game", sess.GameName)
+		checkSchedule(sess.Address, sess, time.Now())
+		checkBudgets()
+
+	case control.CommandSessionEnd:
+		sess, exists := activeSessions[reported.Address]
+		if !exists {
+			return
+		}
+
+		endTime := time.Now()
+		delete(activeSessions, reported.Address)
+		delete(scheduleFlagged, reported.Address)
+		monitorMetrics.SessionEnded(sess.GameName)
+		publishState()
+
+		ev := mqttSessionEvent(sess)
+		ev.End = endTime.Format(time.RFC3339)
+		ev.DurationSeconds = int64(endTime.Sub(sess.StartTime).Seconds())
+		mqttPublisher.SessionEnded(ev)
+		publishMQTTState()
+		emit(sessionEvent(eventGameEnded, sess, endTime))
+		slog.Info("game ended in gametrak run", "game", sess.GameName)
+	}
+}
+
+// trackedByRun reports whether gametrak run is already recording the game
+// behind a window: one with the class it was run as, or one owned by the
+// process it launched or a descendant of it
+func trackedByRun(address, class string) bool {
+	var pids map[int]bool
+	for runAddress, sess := range activeSessions {
+		if !strings.HasPrefix(runAddress, runPrefix) {
+			continue
+		}
+		if sess.Class == class {
+			return true
+		}
+		if pid, ok := sessionPID(runAddress); ok {
+			if pids == nil {
+				pids = make(map[int]bool)
+			}
+			pids[pid] = true
+			for _, p := range process.Descendants(pid) {
+				pids[p] = true
+			}
+		}
+	}
+	if len(pids) == 0 {
+		return false
+	}
+
+	pid, err := hyprland.WindowPID(address)
+	return err == nil && pids[pid]
+}
+
+func init() {
+	runCmd.Flags().StringVar(&runName, "name", "", "name to record the game under")
+	runCmd.Flags().StringVar(&runClass, "class", "", "class identifying the game (default: Steam app or executable name)")
+	runCmd.Flags().SetInterspersed(false)
+	rootCmd.AddCommand(runCmd)
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/messages"
+	"github.com/austincgause/gametrak/internal/models"
+	"github.com/austincgause/gametrak/internal/notify"
+	"github.com/austincgause/gametrak/internal/schedule"
+	"github.com/austincgause/gametrak/internal/utility"
+	"github.com/spf13/cobra"
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Show the play schedule in effect today",
+	Long: `Show the allowed and denied play times that apply today, both the
+global schedule and any per-game schedules.
+
+Schedules are configured under "schedule" at the top level of the config
+file, or under "schedule" on an individual game:
+
+  schedule:
+    mode: warn            # warn, log or close
+    deny:
+      - days: [weekdays]
+        from: "08:00"
+        to: "16:00"
+      - days: [sun, mon, tue, wed, thu]
+        from: "22:00"
+        to: "06:00"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		now := time.Now()
+		fmt.Printf("Schedule for %s\n\n", now.Format("Monday 2006-01-02"))
+
+		global, err := schedule.Compile(cfg.Schedule, models.ScheduleWarn)
+		if err != nil {
+			return fmt.Errorf("invalid global schedule: %w", err)
+		}
+
+		shown := printRules("All games", global, now)
+
+		for _, g := range cfg.Games {
+			if !g.Schedule.IsSet() {
+				continue
+			}
+			rules, err := schedule.Compile(g.Schedule, global.Mode)
+			if err != nil {
+				return fmt.Errorf("invalid schedule for %s: %w", g.DisplayName(), err)
+			}
+			if printRules(g.DisplayName(), rules, now) {
+				shown = true
+			}
+		}
+
+		if !shown {
+			fmt.Println("No schedule restrictions today.")
+			return nil
+		}
+
+		fmt.Println()
+		return nil
+	},
+}
+
+// printRules prints the windows in effect today for one schedule and
+// reports whether anything was printed
+func printRules(name string, rules schedule.Rules, now time.Time) bool {
+	allow, deny := rules.Today(now)
+	if len(allow) == 0 && len(deny) == 0 {
+		return false
+	}
+
+	status := "allowed now"
+	if rule := rules.Check(now); rule != "" {
+		status = "blocked now: " + rule
+	}
+
+	fmt.Printf("%s (mode: %s, %s)\n", name, rules.Mode, status)
+	for _, w := range allow {
+		fmt.Printf("  allow  %s\n", w)
+	}
+	for _, w := range deny {
+		fmt.Printf("  deny   %s\n", w)
+	}
+	return true
+}
+
+var (
+	globalSchedule  schedule.Rules
+	gameSchedules   = make(map[scheduleKey]schedule.Rules)
+	scheduleFlagged = make(map[string]bool)
+)
+
+// scheduleKey identifies a configured game the way utility.MatchGame
+// distinguishes them, so an exact class and a prefix of the same class
+// keep their own schedules
+type scheduleKey struct {
+	class  string
+	prefix bool
+}
+
+// loadSchedules compiles the global and per-game schedules for the monitor.
+// Invalid schedules are reported and ignored.
+func loadSchedules() {
+	var err error
+	globalSchedule, err = schedule.Compile(cfg.Schedule, models.ScheduleWarn)
+	if err != nil {
+		slog.Warn("ignoring invalid global schedule", "error", err)
+		globalSchedule = schedule.Rules{Mode: models.ScheduleWarn}
+	}
+
+	for _, g := range cfg.Games {
+		if !g.Schedule.IsSet() {
+			continue
+		}
+		rules, err := schedule.Compile(g.Schedule, globalSchedule.Mode)
+		if err != nil {
+			slog.Warn("ignoring invalid schedule", "game", g.DisplayName(), "error", err)
+			continue
+		}
+		gameSchedules[scheduleKey{g.Class, g.Prefix}] = rules
+	}
+}
+
+// scheduleViolation returns the violated rule and the mode to apply for a
+// session of the given class at t, or an empty rule if play is permitted
+func scheduleViolation(class string, t time.Time) (rule, mode string) {
+	if game, ok := utility.MatchGame(class, cfg.Games); ok {
+		if rules, ok := gameSchedules[scheduleKey{game.Class, game.Prefix}]; ok {
+			if rule := rules.Check(t); rule != "" {
+				return rule, rules.Mode
+			}
+		}
+	}
+
+	return globalSchedule.Check(t), globalSchedule.Mode
+}
+
+// checkSchedule applies the configured schedule mode to an active session
+// played outside its allowed times. Each session is only reported once,
+// but windows in close mode are closed again if they linger.
+func checkSchedule(address string, sess *models.Session, now time.Time) {
+	rule, mode := scheduleViolation(sess.Class, now)
+	if rule == "" {
+		return
+	}
+
+	if scheduleFlagged[address] {
+		if mode == models.ScheduleClose {
+			closeSession(address, sess)
+		}
+		return
+	}
+	scheduleFlagged[address] = true
+
+	data := sessionFields(sess)
+	data.Subject = "Outside Play Schedule"
+	data.Message = fmt.Sprintf("%s - %s", sess.GameName, rule)
+	if mode == models.ScheduleClose {
+		data.Message += ", closing game"
+	}
+	msg := messages.Render(messages.Reminder, data)
+	printMessage(msg)
+
+	if mode == models.ScheduleLog || mode == models.ScheduleClose {
+		v := models.ViolationLog{
+			Time:   now.Format(time.RFC3339),
+			Game:   sess.GameName,
+			Class:  sess.Class,
+			Rule:   rule,
+			Action: mode,
+		}
+		if err := schedule.LogViolation(cfg.Settings.ViolationsFile, v); err != nil {
+			slog.Warn("failed to log violation", "error", err)
+		}
+	}
+
+	if mode == models.ScheduleClose {
+		closeSession(address, sess)
+	}
+
+	if mode != models.ScheduleLog {
+		notifyMessage(notify.EventReminder, notify.UrgencyCritical, msg)
+	}
+}
+
+// checkSchedules re-checks every active session, catching games that were
+// started in an allowed window and are still running once it ends
+func checkSchedules() {
+	now := time.Now()
+	for address, sess := range activeSessions {
+		checkSchedule(address, sess, now)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(scheduleCmd)
+}
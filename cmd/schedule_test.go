@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/models"
+	"github.com/austincgause/gametrak/internal/schedule"
+)
+
+func TestScheduleViolationSameClass(t *testing.T) {
+	saved, savedGlobal, savedGames := cfg, globalSchedule, gameSchedules
+	t.Cleanup(func() { cfg, globalSchedule, gameSchedules = saved, savedGlobal, savedGames })
+
+	deny := func(from, to string) models.Schedule {
+		return models.Schedule{Deny: []models.TimeWindow{{From: from, To: to}}, Mode: models.ScheduleWarn}
+	}
+	cfg = models.Config{Games: []models.Game{
+		{Class: "steam_app_620", Name: "Portal 2", Schedule: deny("08:00", "12:00")},
+		{Class: "steam_app_620", Prefix: true, Name: "Portal 2 tools", Schedule: deny("20:00", "22:00")},
+	}}
+	gameSchedules = make(map[scheduleKey]schedule.Rules)
+	loadSchedules()
+
+	morning := time.Date(2025, 3, 5, 9, 0, 0, 0, time.Local)
+	evening := time.Date(2025, 3, 5, 21, 0, 0, 0, time.Local)
+	for _, tc := range []struct {
+		class string
+		at    time.Time
+		want  string
+	}{
+		// The exact match comes first and keeps its own schedule
+		{"steam_app_620", morning, "denied 08:00-12:00"},
+		{"steam_app_620", evening, ""},
+		// Only the prefix matches the longer class
+		{"steam_app_6200", morning, ""},
+		{"steam_app_6200", evening, "denied 20:00-22:00"},
+	} {
+		if rule, _ := scheduleViolation(tc.class, tc.at); rule != tc.want {
+			t.Errorf("scheduleViolation(%s, %s) = %q, want %q", tc.class, tc.at.Format("15:04"), rule, tc.want)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/baseline"
+	"github.com/austincgause/gametrak/internal/config"
+	"github.com/austincgause/gametrak/internal/models"
+	"github.com/austincgause/gametrak/internal/session"
+	"github.com/austincgause/gametrak/internal/state"
+	"github.com/austincgause/gametrak/internal/web"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAddr string
+	serveUI   bool
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve session history and stats as a JSON API",
+	Long: `Start a read-only HTTP server exposing session history and statistics
+as JSON.
+
+Endpoints:
+  GET /sessions   ?from=YYYY-MM-DD&to=YYYY-MM-DD&period=<filter>&game=<name>
+  GET /stats      ?period=<filter>&game=<name>
+  GET /active     sessions currently tracked by the running monitor
+
+With --ui, a dashboard is also served at / showing daily, weekly and
+monthly totals, per-game charts, a calendar heatmap and the active
+session. It is embedded in the binary and works offline.
+
+The period filter accepts the same values as history and stats:
+today, yesterday, week, month, year or a date (YYYY-MM-DD).
+Game filters are case-insensitive substring matches. As in stats,
+imported baselines count towards /stats only without a period or date
+range.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mux := newAPIHandler()
+		if serveUI {
+			mux.Handle("GET /", web.Handler())
+		}
+		srv := &http.Server{Addr: serveAddr, Handler: mux}
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			<-sigChan
+			srv.Close()
+		}()
+
+		fmt.Printf("Serving on http://%s\n", serveAddr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			return err
+		}
+		return nil
+	},
+}
+
+func newAPIHandler() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /sessions", handleAPISessions)
+	mux.HandleFunc("GET /stats", handleAPIStats)
+	mux.HandleFunc("GET /active", handleAPIActive)
+	return mux
+}
+
+type sessionsResponse struct {
+	Count        int                 `json:"count"`
+	TotalSeconds int64               `json:"total_seconds"`
+	Sessions     []models.SessionLog `json:"sessions"`
+}
+
+type statsResponse struct {
+	Period          string      `json:"period,omitempty"`
+	Game            string      `json:"game,omitempty"`
+	TotalSeconds    int64       `json:"total_seconds"`
+	ImportedSeconds int64       `json:"imported_seconds,omitempty"`
+	SessionCount    int         `json:"sessions"`
+	Games           []*gameStat `json:"games"`
+}
+
+type activeSession struct {
+	state.ActiveSession
+	ElapsedSeconds int64 `json:"elapsed_seconds"`
+}
+
+type activeResponse struct {
+	Running  bool            `json:"running"`
+	Sessions []activeSession `json:"sessions"`
+}
+
+func handleAPISessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := queryAPISessions(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	resp := sessionsResponse{Sessions: []models.SessionLog{}}
+	for _, s := range sessions {
+		resp.Sessions = append(resp.Sessions, s)
+		resp.TotalSeconds += s.DurationSeconds
+	}
+	resp.Count = len(resp.Sessions)
+
+	writeJSON(w, resp)
+}
+
+func handleAPIStats(w http.ResponseWriter, r *http.Request) {
+	sessions, err := queryAPISessions(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	q := r.URL.Query()
+	resp := statsResponse{
+		Period: q.Get("period"),
+		Game:   q.Get("game"),
+		Games:  aggregateStats(sessions),
+	}
+
+	// Imported baselines only count towards all-time totals
+	if q.Get("period") == "" && q.Get("from") == "" && q.Get("to") == "" {
+		baselines, err := baseline.Load(cfg.Settings.BaselinesFile)
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, err)
+			return
+		}
+		resp.Games = addBaselines(resp.Games, baseline.Totals(baselines, resp.Game))
+	}
+
+	if resp.Games == nil {
+		resp.Games = []*gameStat{}
+	}
+	for _, s := range resp.Games {
+		resp.TotalSeconds += s.TotalSeconds
+		resp.ImportedSeconds += s.ImportedSeconds
+		resp.SessionCount += s.SessionCount
+	}
+
+	writeJSON(w, resp)
+}
+
+func handleAPIActive(w http.ResponseWriter, r *http.Request) {
+	snap, running, err := state.Read(config.DefaultStateFile)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := activeResponse{Running: running, Sessions: []activeSession{}}
+	now := time.Now()
+	for _, s := range snap.Sessions {
+		a := activeSession{ActiveSession: s}
+		if start, err := time.Parse(time.RFC3339, s.Start); err == nil {
+			a.ElapsedSeconds = int64(now.Sub(start).Seconds())
+		}
+		resp.Sessions = append(resp.Sessions, a)
+	}
+
+	writeJSON(w, resp)
+}
+
+// queryAPISessions loads the session log and applies the period, game and
+// from/to query parameters
+func queryAPISessions(r *http.Request) ([]models.SessionLog, error) {
+	q := r.URL.Query()
+
+	period := q.Get("period")
+	if period != "" {
+		timeFilter, gameFilter, err := parseFilterArg([]string{period})
+		if err != nil {
+			return nil, err
+		}
+		if gameFilter != "" {
+			return nil, fmt.Errorf("invalid period %q", period)
+		}
+		period = timeFilter
+	}
+
+	from, err := parseAPIDate(q.Get("from"))
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseAPIDate(q.Get("to"))
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := session.LoadAll(cfg.Settings.SessionsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	sessions = filterSessions(sessions, period, q.Get("game"))
+	return filterDateRange(sessions, from, to), nil
+}
+
+// filterDateRange keeps sessions starting on or after from and on or before
+// the day to. Zero times leave that end of the range open.
+func filterDateRange(sessions []models.SessionLog, from, to time.Time) []models.SessionLog {
+	if from.IsZero() && to.IsZero() {
+		return sessions
+	}
+
+	var filtered []models.SessionLog
+	for _, s := range sessions {
+		startTime, err := time.Parse(time.RFC3339, s.Start)
+		if err != nil {
+			continue
+		}
+		if !from.IsZero() && startTime.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !startTime.Before(to.AddDate(0, 0, 1)) {
+			continue
+		}
+		filtered = append(filtered, s)
+	}
+	return filtered
+}
+
+func parseAPIDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (want YYYY-MM-DD)", s)
+	}
+	return t, nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to write response: %v\n", err)
+	}
+}
+
+func writeAPIError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:9274", "address to listen on")
+	serveCmd.Flags().BoolVar(&serveUI, "ui", false, "also serve the web dashboard")
+}
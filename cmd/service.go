@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/austincgause/gametrak/internal/systemd"
+	"github.com/spf13/cobra"
+)
+
+var serviceCmd = &cobra.Command{
+	Use:   "service",
+	Short: "Manage the gametrak systemd user service",
+	Long: `Install, remove or inspect a systemd user service that runs the
+monitor for the length of the graphical session.
+
+The service needs Hyprland's environment. Make sure it is exported to the
+systemd user manager, for example in hyprland.conf:
+
+  exec-once = dbus-update-activation-environment --systemd --all`,
+}
+
+var serviceInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install and start the user service",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to locate gametrak executable: %w", err)
+		}
+		if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+			exe = resolved
+		}
+
+		command := []string{exe}
+		if cfgFile != "" {
+			abs, err := filepath.Abs(cfgFile)
+			if err != nil {
+				return err
+			}
+			command = append(command, "--config", abs)
+		}
+
+		if err := systemd.Install(command); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %s\n", systemd.UnitPath)
+
+		if err := systemd.Systemctl("daemon-reload"); err != nil {
+			return err
+		}
+		if err := systemd.Systemctl("enable", "--now", systemd.UnitName); err != nil {
+			return err
+		}
+
+		fmt.Println("Gametrak service enabled and started.")
+		return nil
+	},
+}
+
+var serviceUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Stop and remove the user service",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := os.Stat(systemd.UnitPath); os.IsNotExist(err) {
+			fmt.Println("Gametrak service is not installed.")
+			return nil
+		}
+
+		if err := systemd.Systemctl("disable", "--now", systemd.UnitName); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		if err := systemd.Uninstall(); err != nil {
+			return err
+		}
+		if err := systemd.Systemctl("daemon-reload"); err != nil {
+			return err
+		}
+
+		fmt.Printf("Removed %s\n", systemd.UnitPath)
+		return nil
+	},
+}
+
+var serviceStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the user service's status",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := os.Stat(systemd.UnitPath); os.IsNotExist(err) {
+			fmt.Println("Gametrak service is not installed.")
+			return nil
+		}
+
+		err := systemd.Systemctl("status", "--no-pager", systemd.UnitName)
+		// systemctl status exits non-zero for stopped services, which
+		// isn't an error here
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil
+		}
+		return err
+	},
+}
+
+func init() {
+	serviceCmd.AddCommand(serviceInstallCmd)
+	serviceCmd.AddCommand(serviceUninstallCmd)
+	serviceCmd.AddCommand(serviceStatusCmd)
+	rootCmd.AddCommand(serviceCmd)
+}
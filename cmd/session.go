@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/models"
+	"github.com/austincgause/gametrak/internal/session"
+	"github.com/austincgause/gametrak/internal/utility"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sessionLimit int
+	sessionAll   bool
+	editGame     string
+	editStart    string
+	editEnd      string
+	editDuration string
+)
+
+var sessionCmd = &cobra.Command{
+	Use:   "session",
+	Short: "List, edit and delete logged sessions",
+	Long: `Work with individual logged sessions by their ID.
+
+IDs are shown by 'gametrak session list'. Any unique prefix of an ID is
+accepted.`,
+}
+
+var sessionListCmd = &cobra.Command{
+	Use:   "list [today|yesterday|week|month|year|YYYY-MM-DD|<game>]",
+	Short: "List logged sessions with their IDs",
+	Long: `List logged sessions, most recent first, with the IDs used by the
+other session commands.
+
+By default shows the last 10 sessions. Use --all to show all sessions
+or --limit to specify a different number.`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{"today", "yesterday", "week", "month", "year"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sessions, err := session.LoadAll(cfg.Settings.SessionsFile)
+		if err != nil {
+			return fmt.Errorf("failed to load sessions: %w", err)
+		}
+
+		timeFilter, gameFilter, err := parseFilterArg(args)
+		if err != nil {
+			return err
+		}
+		sessions = filterSessions(sessions, timeFilter, gameFilter)
+		sortByStart(sessions)
+		if len(sessions) == 0 {
+			fmt.Println("No sessions found.")
+			return nil
+		}
+
+		count := len(sessions)
+		if !sessionAll && sessionLimit > 0 && sessionLimit < count {
+			count = sessionLimit
+		}
+
+		maxGameLen := 0
+		for _, s := range sessions[len(sessions)-count:] {
+			maxGameLen = max(maxGameLen, len(s.Game))
+		}
+		for i := len(sessions) - 1; i >= len(sessions)-count; i-- {
+			s := sessions[i]
+			fmt.Printf("  %s  %s  %-*s  %s\n", s.ID, formatLogStart(s), maxGameLen, s.Game,
+				utility.FormatDurationExact(time.Duration(s.DurationSeconds)*time.Second))
+		}
+		return nil
+	},
+}
+
+var sessionShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show a logged session",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sessions, err := session.LoadAll(cfg.Settings.SessionsFile)
+		if err != nil {
+			return fmt.Errorf("failed to load sessions: %w", err)
+		}
+		i, err := session.Find(sessions, args[0])
+		if err != nil {
+			return err
+		}
+
+		s := sessions[i]
+		fmt.Printf("ID:       %s\n", s.ID)
+		fmt.Printf("Game:     %s\n", s.Game)
+		if s.Class != "" {
+			fmt.Printf("Class:    %s\n", s.Class)
+		}
+		fmt.Printf("Start:    %s\n", formatLogStart(s))
+		if end, err := time.Parse(time.RFC3339, s.End); err == nil {
+			fmt.Printf("End:      %s\n", formatLogTime(end))
+		}
+		fmt.Printf("Duration: %s\n", utility.FormatDurationExact(time.Duration(s.DurationSeconds)*time.Second))
+		if s.Source != "" {
+			fmt.Printf("Source:   %s\n", s.Source)
+		}
+		return nil
+	},
+}
+
+var sessionEditCmd = &cobra.Command{
+	Use:   "edit <id>",
+	Short: "Change a logged session",
+	Long: `Change the game, start, end or duration of a logged session.
+
+Times are accepted in the same forms as 'gametrak log add'. Changing only
+the start moves the whole session; --duration sets the end relative to the
+start.
+
+Examples:
+  gametrak session edit 3f9a2c1e --duration 45m
+  gametrak session edit 3f9a --game "Elden Ring"
+  gametrak session edit 3f9a --start "yesterday 20:00" --end "yesterday 22:00"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if editGame == "" && editStart == "" && editEnd == "" && editDuration == "" {
+			return fmt.Errorf("nothing to change; give --game, --start, --end or --duration")
+		}
+		if editEnd != "" && editDuration != "" {
+			return fmt.Errorf("give only one of --end or --duration")
+		}
+
+		var original, edited models.SessionLog
+		var description string
+		err := session.Rewrite(cfg.Settings.SessionsFile, func(sessions []models.SessionLog) ([]models.SessionLog, error) {
+			i, err := session.Find(sessions, args[0])
+			if err != nil {
+				return nil, err
+			}
+			original = sessions[i]
+			if edited, err = editSession(sessions[i]); err != nil {
+				return nil, err
+			}
+			if overlaps := session.Overlapping(sessions, edited); len(overlaps) > 0 {
+				return nil, fmt.Errorf("would overlap session %s (%s)", overlaps[0].ID, formatLogStart(overlaps[0]))
+			}
+			sessions[i] = edited
+			return sessions, nil
+		}, func() error {
+			description = fmt.Sprintf("Updated %s: %s, %s, %s", edited.ID, edited.Game, formatLogStart(edited),
+				utility.FormatDurationExact(time.Duration(edited.DurationSeconds)*time.Second))
+			recordChange("session edit", description, []models.SessionLog{original}, []models.SessionLog{edited})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(description)
+		return nil
+	},
+}
+
+var sessionDeleteCmd = &cobra.Command{
+	Use:   "delete <id>",
+	Short: "Delete a logged session",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deleted models.SessionLog
+		var description string
+		err := session.Rewrite(cfg.Settings.SessionsFile, func(sessions []models.SessionLog) ([]models.SessionLog, error) {
+			i, err := session.Find(sessions, args[0])
+			if err != nil {
+				return nil, err
+			}
+			deleted = sessions[i]
+			return append(sessions[:i], sessions[i+1:]...), nil
+		}, func() error {
+			description = fmt.Sprintf("Deleted %s: %s, %s, %s", deleted.ID, deleted.Game, formatLogStart(deleted),
+				utility.FormatDurationExact(time.Duration(deleted.DurationSeconds)*time.Second))
+			recordChange("session delete", description, []models.SessionLog{deleted}, nil)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(description)
+		return nil
+	},
+}
+
+// editSession applies the edit flags to a session
+func editSession(s models.SessionLog) (models.SessionLog, error) {
+	now := time.Now()
+	start, err := time.Parse(time.RFC3339, s.Start)
+	if err != nil {
+		return s, fmt.Errorf("session %s has an invalid start: %w", s.ID, err)
+	}
+	end, err := time.Parse(time.RFC3339, s.End)
+	if err != nil {
+		return s, fmt.Errorf("session %s has an invalid end: %w", s.ID, err)
+	}
+
+	if editGame != "" {
+		// The class decides which budgets and schedules a session counts
+		// against, so it can't be kept for a different game
+		if !strings.EqualFold(editGame, s.Game) {
+			s.Class = ""
+		}
+		s.Game = editGame
+		if game, ok := configuredGame(editGame); ok {
+			s.Game = game.DisplayName()
+			s.Class = game.Class
+		}
+	}
+	if editStart != "" {
+		duration := end.Sub(start)
+		if start, err = utility.ParseTime(editStart, now); err != nil {
+			return s, err
+		}
+		end = start.Add(duration)
+	}
+	if editEnd != "" {
+		if end, err = utility.ParseTime(editEnd, now); err != nil {
+			return s, err
+		}
+	}
+	if editDuration != "" {
+		mins, err := parseMinutes(editDuration)
+		if err != nil {
+			return s, err
+		}
+		end = start.Add(time.Duration(mins) * time.Minute)
+	}
+
+	if !end.After(start) {
+		return s, fmt.Errorf("session must end after it starts (%s to %s)", formatLogTime(start), formatLogTime(end))
+	}
+	if end.After(now) {
+		return s, fmt.Errorf("session can't end in the future (%s)", formatLogTime(end))
+	}
+
+	s.Start = start.Format(time.RFC3339)
+	s.End = end.Format(time.RFC3339)
+	s.DurationSeconds = int64(end.Sub(start).Seconds())
+	return s, nil
+}
+
+// formatLogStart formats a logged session's start time for display
+func formatLogStart(s models.SessionLog) string {
+	start, err := time.Parse(time.RFC3339, s.Start)
+	if err != nil {
+		return s.Start
+	}
+	return formatLogTime(start)
+}
+
+func init() {
+	sessionListCmd.Flags().IntVarP(&sessionLimit, "limit", "l", 10, "number of sessions to show")
+	sessionListCmd.Flags().BoolVarP(&sessionAll, "all", "a", false, "show all sessions")
+	sessionEditCmd.Flags().StringVar(&editGame, "game", "", "new game name")
+	sessionEditCmd.Flags().StringVar(&editStart, "start", "", "new start time")
+	sessionEditCmd.Flags().StringVar(&editEnd, "end", "", "new end time")
+	sessionEditCmd.Flags().StringVar(&editDuration, "duration", "", "new duration, in minutes or as a duration such as 2h30m")
+
+	sessionCmd.AddCommand(sessionListCmd)
+	sessionCmd.AddCommand(sessionShowCmd)
+	sessionCmd.AddCommand(sessionEditCmd)
+	sessionCmd.AddCommand(sessionDeleteCmd)
+	rootCmd.AddCommand(sessionCmd)
+}
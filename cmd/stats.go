@@ -6,6 +6,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/austincgause/gametrak/internal/baseline"
+	"github.com/austincgause/gametrak/internal/models"
 	"github.com/austincgause/gametrak/internal/session"
 	"github.com/austincgause/gametrak/internal/utility"
 	"github.com/spf13/cobra"
@@ -19,7 +21,10 @@ var statsCmd = &cobra.Command{
 - Most played games
 - Session counts
 
-Time filters: today, yesterday, week, month, year, or a specific date (YYYY-MM-DD).`,
+Time filters: today, yesterday, week, month, year, or a specific date (YYYY-MM-DD).
+
+Playtime imported from other launchers is included in all-time totals,
+but not when a time filter is given.`,
 	Args:      cobra.MaximumNArgs(1),
 	ValidArgs: []string{"today", "yesterday", "week", "month", "year"},
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -28,58 +33,43 @@ Time filters: today, yesterday, week, month, year, or a specific date (YYYY-MM-D
 			return fmt.Errorf("failed to load sessions: %w", err)
 		}
 
-		if len(sessions) == 0 {
-			fmt.Println("No sessions recorded yet.")
-			return nil
-		}
-
 		timeFilter, gameFilter, err := parseFilterArg(args)
 		if err != nil {
 			return err
 		}
 
+		// Imported baselines only count towards all-time totals
+		var imported map[string]int64
+		if timeFilter == "" {
+			baselines, err := baseline.Load(cfg.Settings.BaselinesFile)
+			if err != nil {
+				return err
+			}
+			imported = baseline.Totals(baselines, gameFilter)
+		}
+
+		if len(sessions) == 0 && len(imported) == 0 {
+			fmt.Println("No sessions recorded yet.")
+			return nil
+		}
+
 		// Apply filters
 		sessions = filterSessions(sessions, timeFilter, gameFilter)
 
-		if len(sessions) == 0 {
+		if len(sessions) == 0 && len(imported) == 0 {
 			fmt.Println("No sessions match the filter criteria.")
 			return nil
 		}
 
-		// Aggregate stats per game
-		type gameStat struct {
-			name         string
-			totalSeconds int64
-			sessionCount int
-		}
-
-		gameStats := make(map[string]*gameStat)
-
-		for _, s := range sessions {
-			stat, exists := gameStats[s.Game]
-			if !exists {
-				stat = &gameStat{name: s.Game}
-				gameStats[s.Game] = stat
-			}
-			stat.totalSeconds += s.DurationSeconds
-			stat.sessionCount++
-		}
-
-		// Sort by total time
-		var stats []*gameStat
-		for _, s := range gameStats {
-			stats = append(stats, s)
-		}
-		sort.Slice(stats, func(i, j int) bool {
-			return stats[i].totalSeconds > stats[j].totalSeconds
-		})
+		stats := addBaselines(aggregateStats(sessions), imported)
 
 		// Calculate total
-		var totalSeconds int64
+		var totalSeconds, importedSeconds int64
 		var totalSessions int
 		for _, s := range stats {
-			totalSeconds += s.totalSeconds
-			totalSessions += s.sessionCount
+			totalSeconds += s.TotalSeconds
+			importedSeconds += s.ImportedSeconds
+			totalSessions += s.SessionCount
 		}
 
 		// Build header
@@ -92,9 +82,14 @@ Time filters: today, yesterday, week, month, year, or a specific date (YYYY-MM-D
 		fmt.Printf("%s\n", header)
 		fmt.Printf("%s\n\n", strings.Repeat("=", len(header)))
 
-		fmt.Printf("Total: %s across %d sessions\n\n",
+		fmt.Printf("Total: %s across %d sessions",
 			utility.FormatDurationRounded(time.Duration(totalSeconds)*time.Second),
 			totalSessions)
+		if importedSeconds > 0 {
+			fmt.Printf(" (including %s imported)",
+				utility.FormatDurationRounded(time.Duration(importedSeconds)*time.Second))
+		}
+		fmt.Printf("\n\n")
 
 		// Break durations into separate columns for alignment
 		type row struct {
@@ -105,11 +100,11 @@ Time filters: today, yesterday, week, month, year, or a specific date (YYYY-MM-D
 		maxNameLen := 0
 		hasHours := false
 		for i, s := range stats {
-			if len(s.name) > maxNameLen {
-				maxNameLen = len(s.name)
+			if len(s.Game) > maxNameLen {
+				maxNameLen = len(s.Game)
 			}
-			r := utility.RoundDuration(time.Duration(s.totalSeconds) * time.Second)
-			rows[i] = row{hours: r.Hours, mins: r.Mins, sessions: s.sessionCount}
+			r := utility.RoundDuration(time.Duration(s.TotalSeconds) * time.Second)
+			rows[i] = row{hours: r.Hours, mins: r.Mins, sessions: s.SessionCount}
 			if r.Hours > 0 {
 				hasHours = true
 			}
@@ -126,15 +121,20 @@ Time filters: today, yesterday, week, month, year, or a specific date (YYYY-MM-D
 			var line string
 			if hasHours {
 				if r.hours > 0 {
-					line = fmt.Sprintf("  %-*s  %2d %-5s  %2d mins", maxNameLen, s.name, r.hours, hourWord, r.mins)
+					line = fmt.Sprintf("  %-*s  %2d %-5s  %2d mins", maxNameLen, s.Game, r.hours, hourWord, r.mins)
 				} else {
-					line = fmt.Sprintf("  %-*s            %2d mins", maxNameLen, s.name, r.mins)
+					line = fmt.Sprintf("  %-*s            %2d mins", maxNameLen, s.Game, r.mins)
 				}
 			} else {
-				line = fmt.Sprintf("  %-*s  %2d mins", maxNameLen, s.name, r.mins)
+				line = fmt.Sprintf("  %-*s  %2d mins", maxNameLen, s.Game, r.mins)
 			}
 
-			fmt.Printf("%s  (%d sessions)\n", line, r.sessions)
+			if s.ImportedSeconds > 0 {
+				fmt.Printf("%s  (%d sessions, %s imported)\n", line, r.sessions,
+					utility.FormatDurationRounded(time.Duration(s.ImportedSeconds)*time.Second))
+			} else {
+				fmt.Printf("%s  (%d sessions)\n", line, r.sessions)
+			}
 		}
 
 		fmt.Println()
@@ -142,6 +142,75 @@ Time filters: today, yesterday, week, month, year, or a specific date (YYYY-MM-D
 	},
 }
 
+// gameStat is the aggregate playtime for one game. TotalSeconds includes
+// any ImportedSeconds.
+type gameStat struct {
+	Game            string `json:"game"`
+	TotalSeconds    int64  `json:"total_seconds"`
+	ImportedSeconds int64  `json:"imported_seconds,omitempty"`
+	SessionCount    int    `json:"sessions"`
+}
+
+// aggregateStats totals sessions per game, most played first
+func aggregateStats(sessions []models.SessionLog) []*gameStat {
+	gameStats := make(map[string]*gameStat)
+
+	for _, s := range sessions {
+		stat, exists := gameStats[s.Game]
+		if !exists {
+			stat = &gameStat{Game: s.Game}
+			gameStats[s.Game] = stat
+		}
+		stat.TotalSeconds += s.DurationSeconds
+		stat.SessionCount++
+	}
+
+	// Sort by total time
+	var stats []*gameStat
+	for _, s := range gameStats {
+		stats = append(stats, s)
+	}
+	sortStats(stats)
+
+	return stats
+}
+
+// addBaselines adds imported playtime per game to the stats, keeping them
+// sorted by total time
+func addBaselines(stats []*gameStat, imported map[string]int64) []*gameStat {
+	if len(imported) == 0 {
+		return stats
+	}
+
+	byGame := make(map[string]*gameStat, len(stats))
+	for _, s := range stats {
+		byGame[s.Game] = s
+	}
+
+	for game, secs := range imported {
+		stat, exists := byGame[game]
+		if !exists {
+			stat = &gameStat{Game: game}
+			byGame[game] = stat
+			stats = append(stats, stat)
+		}
+		stat.ImportedSeconds += secs
+		stat.TotalSeconds += secs
+	}
+
+	sortStats(stats)
+	return stats
+}
+
+func sortStats(stats []*gameStat) {
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].TotalSeconds != stats[j].TotalSeconds {
+			return stats[i].TotalSeconds > stats[j].TotalSeconds
+		}
+		return stats[i].Game < stats[j].Game
+	})
+}
+
 func init() {
 	rootCmd.AddCommand(statsCmd)
 }
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/config"
+	"github.com/austincgause/gametrak/internal/control"
+	"github.com/austincgause/gametrak/internal/state"
+	"github.com/austincgause/gametrak/internal/timers"
+	"github.com/austincgause/gametrak/internal/utility"
+	"github.com/spf13/cobra"
+)
+
+var watchOutput string
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show what the running monitor is tracking",
+	Long: `Ask the running monitor which games are being played right now,
+and list any manual timers started with 'gametrak start'.
+
+The monitor answers over its control socket in the runtime directory.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		running, err := timers.Load(config.DefaultTimers)
+		if err != nil {
+			return err
+		}
+
+		var snap state.Snapshot
+		if err := control.Status(config.DefaultControl, &snap); err != nil {
+			if err != control.ErrNotRunning {
+				return err
+			}
+			fmt.Println("Gametrak is not running.")
+		} else {
+			fmt.Printf("Gametrak is running (pid %d)\n\n", snap.PID)
+			if len(snap.Sessions) == 0 {
+				fmt.Println("No games running.")
+			}
+			for _, s := range snap.Sessions {
+				printActive(s.Game, s.Start)
+			}
+		}
+
+		if len(running) > 0 {
+			fmt.Println("\nManual timers:")
+			for _, t := range running {
+				printActive(t.Game, t.Start)
+			}
+		}
+
+		fmt.Println()
+		return nil
+	},
+}
+
+// printActive prints a game being played and how long it has been running
+func printActive(game, startTime string) {
+	line := "  " + game
+	if start, err := time.Parse(time.RFC3339, startTime); err == nil {
+		line += fmt.Sprintf(" - %s (since %s)", utility.FormatDurationExact(time.Since(start)), start.Local().Format("15:04"))
+	}
+	fmt.Println(line)
+}
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Stream events from the running monitor",
+	Long: `Attach to the running monitor and print game events as they happen.
+
+Any number of watchers can attach at once. A watcher that falls behind
+misses events rather than slowing the monitor down.
+
+Examples:
+  gametrak watch
+  gametrak watch --output json | jq .`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if watchOutput != outputText && watchOutput != outputJSON {
+			return fmt.Errorf("invalid output format %q (expected text or json)", watchOutput)
+		}
+
+		err := control.Watch(config.DefaultControl, func(line []byte) error {
+			if watchOutput == outputJSON {
+				_, err := os.Stdout.Write(append(line, '\n'))
+				return err
+			}
+
+			var ev monitorEvent
+			if err := json.Unmarshal(line, &ev); err != nil {
+				return nil
+			}
+			_, err := fmt.Println(formatEvent(ev))
+			return err
+		})
+		if err == nil {
+			fmt.Fprintln(os.Stderr, "Gametrak stopped.")
+		}
+		return err
+	},
+}
+
+func init() {
+	watchCmd.Flags().StringVarP(&watchOutput, "output", "o", outputText, "output format: text or json")
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(watchCmd)
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/config"
+	"github.com/austincgause/gametrak/internal/models"
+	"github.com/austincgause/gametrak/internal/session"
+	"github.com/austincgause/gametrak/internal/timers"
+	"github.com/austincgause/gametrak/internal/utility"
+	"github.com/spf13/cobra"
+)
+
+var stopAll bool
+
+var startCmd = &cobra.Command{
+	Use:   "start <name>",
+	Short: "Start timing a game played away from this computer",
+	Long: `Start a manual timer for a game gametrak can't see, such as a board
+game or a handheld console. Stop it with 'gametrak stop'.
+
+Timers are kept in a state file, so they survive restarts and don't need
+the monitor. A name matching a configured game is recorded as that game.
+
+Examples:
+  gametrak start "Wingspan"
+  gametrak start "Zelda (Switch)"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		running, err := timers.Load(config.DefaultTimers)
+		if err != nil {
+			return err
+		}
+
+		timer := newTimer(args[0], time.Now())
+		if i := timers.Find(running, timer.Game); i >= 0 {
+			start, _ := time.Parse(time.RFC3339, running[i].Start)
+			return fmt.Errorf("a timer for %s is already running (since %s)", running[i].Game, start.Local().Format("15:04"))
+		}
+
+		if err := timers.Save(config.DefaultTimers, append(running, timer)); err != nil {
+			return err
+		}
+
+		fmt.Printf("Started timer for %s\n", timer.Game)
+		return nil
+	},
+}
+
+var stopCmd = &cobra.Command{
+	Use:   "stop [name]",
+	Short: "Stop a manual timer and log the session",
+	Long: `Stop a timer started with 'gametrak start' and log it as a session.
+
+The name can be left out when only one timer is running. Sessions shorter
+than min_session_mins are discarded, as they are for detected games.
+
+Examples:
+  gametrak stop
+  gametrak stop "Wingspan"
+  gametrak stop --all`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if stopAll && len(args) > 0 {
+			return fmt.Errorf("--all can't be combined with a name")
+		}
+
+		running, err := timers.Load(config.DefaultTimers)
+		if err != nil {
+			return err
+		}
+		if len(running) == 0 {
+			fmt.Println("No timers running.")
+			return nil
+		}
+
+		var stopped []models.Timer
+		var remaining []models.Timer
+		switch {
+		case stopAll:
+			stopped = running
+
+		case len(args) == 1:
+			i := timers.Find(running, args[0])
+			if i < 0 {
+				if game, ok := configuredGame(args[0]); ok {
+					i = timers.Find(running, game.DisplayName())
+				}
+			}
+			if i < 0 {
+				return fmt.Errorf("no timer running for %s", args[0])
+			}
+			stopped = running[i : i+1]
+			remaining = append(append(remaining, running[:i]...), running[i+1:]...)
+
+		case len(running) == 1:
+			stopped = running
+
+		default:
+			return fmt.Errorf("%d timers are running (%s); name one or use --all", len(running), timerNames(running))
+		}
+
+		// Log every timer before saving, keeping any that failed to log so
+		// they can be stopped again
+		end := time.Now()
+		var errs []error
+		for _, t := range stopped {
+			if err := stopTimer(t, end); err != nil {
+				errs = append(errs, err)
+				remaining = append(remaining, t)
+			}
+		}
+
+		if err := timers.Save(config.DefaultTimers, remaining); err != nil {
+			errs = append(errs, err)
+		}
+		return errors.Join(errs...)
+	},
+}
+
+// newTimer starts a timer, recording a configured game under its display
+// name and class
+func newTimer(name string, start time.Time) models.Timer {
+	timer := models.Timer{Game: name, Start: start.Format(time.RFC3339)}
+	if game, ok := configuredGame(name); ok {
+		timer.Game = game.DisplayName()
+		timer.Class = game.Class
+	}
+	return timer
+}
+
+// configuredGame finds the configured game with the given display name
+func configuredGame(name string) (models.Game, bool) {
+	for _, g := range cfg.Games {
+		if strings.EqualFold(g.DisplayName(), name) {
+			return g, true
+		}
+	}
+	return models.Game{}, false
+}
+
+// stopTimer logs a stopped timer as a manual session if it ran long enough
+func stopTimer(t models.Timer, end time.Time) error {
+	start, err := time.Parse(time.RFC3339, t.Start)
+	if err != nil {
+		return fmt.Errorf("invalid start time for %s: %w", t.Game, err)
+	}
+
+	duration := end.Sub(start)
+	minDuration := time.Duration(cfg.Settings.MinSessionMins) * time.Minute
+	if duration < minDuration {
+		fmt.Printf("Stopped %s - %s (shorter than %d minutes, not logged)\n",
+			t.Game, utility.FormatDurationExact(duration), cfg.Settings.MinSessionMins)
+		return nil
+	}
+
+	sess := models.Session{
+		Class:     t.Class,
+		GameName:  t.Game,
+		StartTime: start,
+		Source:    models.SourceManual,
+	}
+	entry := session.Entry(sess, end)
+	description := fmt.Sprintf("Stopped %s - %s", t.Game, utility.FormatDurationExact(duration))
+	err = session.Append(cfg.Settings.SessionsFile, entry, func() error {
+		recordChange("stop", description, nil, []models.SessionLog{entry})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to log %s: %w", t.Game, err)
+	}
+
+	fmt.Println(description)
+	return nil
+}
+
+func timerNames(running []models.Timer) string {
+	names := make([]string, len(running))
+	for i, t := range running {
+		names[i] = t.Game
+	}
+	return strings.Join(names, ", ")
+}
+
+func init() {
+	stopCmd.Flags().BoolVarP(&stopAll, "all", "a", false, "stop every running timer")
+	rootCmd.AddCommand(startCmd)
+	rootCmd.AddCommand(stopCmd)
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/config"
+	"github.com/austincgause/gametrak/internal/journal"
+	"github.com/austincgause/gametrak/internal/models"
+	"github.com/austincgause/gametrak/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var undoList bool
+
+var undoCmd = &cobra.Command{
+	Use:   "undo [N]",
+	Short: "Undo the last changes made to the session log",
+	Long: `Revert the last N changes made to the session log by hand (default 1),
+newest first.
+
+Changes made with 'log add', 'stop', 'session edit' and 'session delete'
+are journaled; sessions logged by the monitor are not, and are kept when
+undoing. Only the last ` + strconv.Itoa(journal.MaxEntries) + ` changes are remembered.
+
+Examples:
+  gametrak undo --list
+  gametrak undo
+  gametrak undo 3`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if undoList {
+			return listChanges()
+		}
+
+		n := 1
+		if len(args) == 1 {
+			var err error
+			if n, err = strconv.Atoi(args[0]); err != nil || n < 1 {
+				return fmt.Errorf("invalid number of changes: %s", args[0])
+			}
+		}
+
+		// The journal is read and trimmed under the sessions lock, so a
+		// change can't be journaled in between. All N changes are reverted
+		// in one rewrite, so either all of them are undone or none are.
+		var entries, undone []models.JournalEntry
+		err := session.Rewrite(cfg.Settings.SessionsFile, func(sessions []models.SessionLog) ([]models.SessionLog, error) {
+			var err error
+			if entries, err = journal.Load(config.DefaultJournal); err != nil {
+				return nil, err
+			}
+			if len(entries) == 0 {
+				return nil, errNothingToUndo
+			}
+			if n > len(entries) {
+				return nil, fmt.Errorf("only %d changes can be undone", len(entries))
+			}
+
+			undone = entries[len(entries)-n:]
+			for i := len(undone) - 1; i >= 0; i-- {
+				if sessions, err = journal.Revert(sessions, undone[i]); err != nil {
+					return nil, fmt.Errorf("can't undo %q: %w", undone[i].Description, err)
+				}
+			}
+			return sessions, nil
+		}, func() error {
+			return journal.Save(config.DefaultJournal, entries[:len(entries)-n])
+		})
+		if err == errNothingToUndo {
+			fmt.Println("Nothing to undo.")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for i := len(undone) - 1; i >= 0; i-- {
+			fmt.Printf("Undid %s: %s\n", undone[i].Command, undone[i].Description)
+		}
+		return nil
+	},
+}
+
+var errNothingToUndo = errors.New("nothing to undo")
+
+// listChanges prints the journaled changes, newest first
+func listChanges() error {
+	entries, err := journal.Load(config.DefaultJournal)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println("Nothing to undo.")
+		return nil
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		when := e.Time
+		if t, err := time.Parse(time.RFC3339, e.Time); err == nil {
+			when = formatLogTime(t)
+		}
+		fmt.Printf("  %2d  %s  %-14s  %s\n", len(entries)-i, when, e.Command, e.Description)
+	}
+	return nil
+}
+
+// recordChange journals a change to the session log so it can be undone.
+// It must be called while the sessions file is locked, from the committed
+// callback of session.Rewrite or session.Append. The change has already
+// been made, so a failure is only reported.
+func recordChange(command, description string, before, after []models.SessionLog) {
+	entry := models.JournalEntry{
+		Command:     command,
+		Description: description,
+		Before:      before,
+		After:       after,
+	}
+	if err := journal.Record(config.DefaultJournal, entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: change can't be undone: %v\n", err)
+	}
+}
+
+func init() {
+	undoCmd.Flags().BoolVarP(&undoList, "list", "l", false, "list the changes that can be undone")
+	rootCmd.AddCommand(undoCmd)
+}
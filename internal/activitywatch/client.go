@@ -0,0 +1,173 @@
+package activitywatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/models"
+)
+
+// BucketType identifies gametrak's sessions among other ActivityWatch data
+const BucketType = "app.gametrak.session"
+
+// requestTimeout bounds calls so an unresponsive server can't stall callers
+const requestTimeout = 10 * time.Second
+
+// Event is an ActivityWatch event
+type Event struct {
+	Timestamp time.Time      `json:"timestamp"`
+	Duration  float64        `json:"duration"`
+	Data      map[string]any `json:"data"`
+}
+
+// Client talks to an aw-server's REST API. The bucket is created on first
+// use.
+type Client struct {
+	baseURL string
+	bucket  string
+	http    *http.Client
+
+	mu      sync.Mutex
+	created bool
+}
+
+// New returns a client for the aw-server at baseURL that stores events in
+// the named bucket
+func New(baseURL, bucket string) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		bucket:  bucket,
+		http:    &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// FromSession converts a logged session to an event
+func FromSession(s models.SessionLog) (Event, error) {
+	start, err := time.Parse(time.RFC3339, s.Start)
+	if err != nil {
+		return Event{}, fmt.Errorf("invalid session start %q: %w", s.Start, err)
+	}
+	e := Event{
+		Timestamp: start.UTC(),
+		Duration:  float64(s.DurationSeconds),
+		Data: map[string]any{
+			"game":  s.Game,
+			"class": s.Class,
+		},
+	}
+	if s.Source != "" {
+		e.Data["source"] = s.Source
+	}
+	return e, nil
+}
+
+// EnsureBucket creates the bucket if it doesn't exist yet
+func (c *Client) EnsureBucket() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.created {
+		return nil
+	}
+
+	host, _ := os.Hostname()
+	body := map[string]string{
+		"client":   "gametrak",
+		"type":     BucketType,
+		"hostname": host,
+	}
+	// aw-server answers 304 Not Modified when the bucket already exists
+	if err := c.do(http.MethodPost, c.bucketPath(""), nil, body, nil, http.StatusNotModified); err != nil {
+		return fmt.Errorf("failed to create bucket: %w", err)
+	}
+
+	c.created = true
+	return nil
+}
+
+// Insert adds events to the bucket
+func (c *Client) Insert(events []Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := c.EnsureBucket(); err != nil {
+		return err
+	}
+	if err := c.do(http.MethodPost, c.bucketPath("/events"), nil, events, nil); err != nil {
+		return fmt.Errorf("failed to insert events: %w", err)
+	}
+	return nil
+}
+
+// Events returns every event in the bucket
+func (c *Client) Events() ([]Event, error) {
+	if err := c.EnsureBucket(); err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	query := url.Values{"limit": {"-1"}}
+	if err := c.do(http.MethodGet, c.bucketPath("/events"), query, nil, &events); err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	return events, nil
+}
+
+func (c *Client) bucketPath(suffix string) string {
+	return "/api/0/buckets/" + url.PathEscape(c.bucket) + suffix
+}
+
+// do sends a JSON request and decodes a JSON response into out, if given.
+// Any 2xx status succeeds, as do the extra statuses listed in ok.
+func (c *Client) do(method, path string, query url.Values, in, out any, ok ...int) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	accepted := resp.StatusCode >= 200 && resp.StatusCode < 300
+	for _, code := range ok {
+		if resp.StatusCode == code {
+			accepted = true
+		}
+	}
+	if !accepted {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	if out != nil && resp.StatusCode != http.StatusNotModified {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package activitywatch
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/models"
+)
+
+const testBucket = "gametrak_test"
+
+// fakeServer implements the parts of aw-server's bucket API gametrak uses
+type fakeServer struct {
+	mu      sync.Mutex
+	exists  bool
+	fail    bool
+	creates int
+	bucket  map[string]string
+	events  []Event
+}
+
+// newTestClient serves f over HTTP and returns a client for it
+func newTestClient(t *testing.T, f *fakeServer) *Client {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/0/buckets/{bucket}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.creates++
+		switch {
+		case f.fail:
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		case f.exists:
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			json.NewDecoder(r.Body).Decode(&f.bucket)
+			f.exists = true
+		}
+	})
+	mux.HandleFunc("POST /api/0/buckets/{bucket}/events", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if !f.exists || r.PathValue("bucket") != testBucket {
+			http.Error(w, "no such bucket", http.StatusNotFound)
+			return
+		}
+		var events []Event
+		if err := json.NewDecoder(r.Body).Decode(&events); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.events = append(f.events, events...)
+	})
+	mux.HandleFunc("GET /api/0/buckets/{bucket}/events", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if r.URL.Query().Get("limit") != "-1" {
+			http.Error(w, "expected limit=-1", http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(append([]Event{}, f.events...))
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return New(srv.URL+"/", testBucket)
+}
+
+func testSession(game, start string, seconds int64) models.SessionLog {
+	return models.SessionLog{Game: game, Class: game + "-class", Start: start, DurationSeconds: seconds}
+}
+
+func TestEnsureBucket(t *testing.T) {
+	f := &fakeServer{}
+	c := newTestClient(t, f)
+
+	if err := c.EnsureBucket(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.EnsureBucket(); err != nil {
+		t.Fatal(err)
+	}
+
+	if f.creates != 1 {
+		t.Errorf("bucket created %d times, want once", f.creates)
+	}
+	if f.bucket["client"] != "gametrak" || f.bucket["type"] != BucketType {
+		t.Errorf("bucket = %v", f.bucket)
+	}
+}
+
+func TestEnsureBucketExists(t *testing.T) {
+	f := &fakeServer{exists: true}
+	c := newTestClient(t, f)
+
+	if err := c.EnsureBucket(); err != nil {
+		t.Fatalf("existing bucket: %v", err)
+	}
+	if f.bucket != nil {
+		t.Errorf("existing bucket was replaced: %v", f.bucket)
+	}
+}
+
+func TestEnsureBucketRetriesAfterFailure(t *testing.T) {
+	f := &fakeServer{fail: true}
+	c := newTestClient(t, f)
+
+	if err := c.EnsureBucket(); err == nil {
+		t.Fatal("expected an error from a failing server")
+	}
+
+	f.fail = false
+	if err := c.EnsureBucket(); err != nil {
+		t.Fatal(err)
+	}
+	if f.creates != 2 {
+		t.Errorf("bucket creation tried %d times, want 2", f.creates)
+	}
+}
+
+func TestInsert(t *testing.T) {
+	f := &fakeServer{}
+	c := newTestClient(t, f)
+
+	e, err := FromSession(testSession("Factorio", "2025-01-01T20:00:00+01:00", 3600))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Insert([]Event{e}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(f.events) != 1 {
+		t.Fatalf("server has %d events, want 1", len(f.events))
+	}
+	got := f.events[0]
+	if want := time.Date(2025, 1, 1, 19, 0, 0, 0, time.UTC); !got.Timestamp.Equal(want) {
+		t.Errorf("timestamp = %s, want %s", got.Timestamp, want)
+	}
+	if got.Duration != 3600 || got.Data["game"] != "Factorio" || got.Data["class"] != "Factorio-class" {
+		t.Errorf("event = %+v", got)
+	}
+}
+
+func TestInsertNothing(t *testing.T) {
+	f := &fakeServer{}
+	c := newTestClient(t, f)
+
+	if err := c.Insert(nil); err != nil {
+		t.Fatal(err)
+	}
+	if f.creates != 0 {
+		t.Error("inserting no events contacted the server")
+	}
+}
+
+func TestFromSessionInvalidStart(t *testing.T) {
+	if _, err := FromSession(testSession("Factorio", "yesterday", 60)); err == nil {
+		t.Error("expected an error for an invalid start time")
+	}
+}
+
+func TestFromSessionSource(t *testing.T) {
+	s := testSession("Factorio", "2025-01-01T20:00:00Z", 60)
+	e, _ := FromSession(s)
+	if _, ok := e.Data["source"]; ok {
+		t.Errorf("detected session has a source: %v", e.Data)
+	}
+
+	s.Source = models.SourceRun
+	e, _ = FromSession(s)
+	if e.Data["source"] != models.SourceRun {
+		t.Errorf("source = %v, want %s", e.Data["source"], models.SourceRun)
+	}
+}
@@ -0,0 +1,55 @@
+package activitywatch
+
+import (
+	"github.com/austincgause/gametrak/internal/models"
+)
+
+// SyncResult counts what Sync did with the session history
+type SyncResult struct {
+	Sent    int
+	Skipped int
+	Invalid int
+}
+
+// Sync sends every session the bucket doesn't already have. Sessions are
+// matched on start time and game, so running it again only sends new ones.
+func (c *Client) Sync(sessions []models.SessionLog) (SyncResult, error) {
+	var result SyncResult
+
+	existing, err := c.Events()
+	if err != nil {
+		return result, err
+	}
+
+	seen := make(map[string]bool, len(existing))
+	for _, e := range existing {
+		seen[eventKey(e)] = true
+	}
+
+	var pending []Event
+	for _, s := range sessions {
+		e, err := FromSession(s)
+		if err != nil {
+			result.Invalid++
+			continue
+		}
+		key := eventKey(e)
+		if seen[key] {
+			result.Skipped++
+			continue
+		}
+		seen[key] = true
+		pending = append(pending, e)
+	}
+
+	if err := c.Insert(pending); err != nil {
+		return result, err
+	}
+	result.Sent = len(pending)
+	return result, nil
+}
+
+func eventKey(e Event) string {
+	game, _ := e.Data["game"].(string)
+	return e.Timestamp.UTC().Format("2006-01-02T15:04:05") + "|" + game
+}
@@ -0,0 +1,65 @@
+package activitywatch
+
+import (
+	"testing"
+
+	"github.com/austincgause/gametrak/internal/models"
+)
+
+func TestSync(t *testing.T) {
+	f := &fakeServer{}
+	c := newTestClient(t, f)
+
+	existing, err := FromSession(testSession("Factorio", "2025-01-01T20:00:00Z", 3600))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Insert([]Event{existing}); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions := []models.SessionLog{
+		// Already in the bucket, given in another time zone
+		testSession("Factorio", "2025-01-01T21:00:00+01:00", 3600),
+		testSession("Factorio", "2025-01-02T20:00:00Z", 1800),
+		// Same start, different game
+		testSession("Celeste", "2025-01-02T20:00:00Z", 600),
+		// Repeated within the history
+		testSession("Celeste", "2025-01-02T20:00:00Z", 600),
+		testSession("Celeste", "not a time", 600),
+	}
+
+	result, err := c.Sync(sessions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (SyncResult{Sent: 2, Skipped: 2, Invalid: 1}); result != want {
+		t.Errorf("first sync = %+v, want %+v", result, want)
+	}
+	if len(f.events) != 3 {
+		t.Errorf("server has %d events, want 3", len(f.events))
+	}
+
+	result, err = c.Sync(sessions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (SyncResult{Skipped: 4, Invalid: 1}); result != want {
+		t.Errorf("second sync = %+v, want %+v", result, want)
+	}
+	if len(f.events) != 3 {
+		t.Errorf("server has %d events after syncing again, want 3", len(f.events))
+	}
+}
+
+func TestSyncServerDown(t *testing.T) {
+	f := &fakeServer{fail: true}
+	c := newTestClient(t, f)
+
+	if _, err := c.Sync([]models.SessionLog{testSession("Factorio", "2025-01-01T20:00:00Z", 60)}); err == nil {
+		t.Error("expected an error when the server fails")
+	}
+	if len(f.events) != 0 {
+		t.Errorf("server has %d events, want none", len(f.events))
+	}
+}
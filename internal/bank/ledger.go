@@ -0,0 +1,88 @@
+package bank
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/models"
+)
+
+// Append adds a transaction to the end of the JSONL ledger file
+func Append(bankFile string, tx models.BankTransaction) error {
+	if err := os.MkdirAll(filepath.Dir(bankFile), 0755); err != nil {
+		return fmt.Errorf("failed to create bank directory: %w", err)
+	}
+
+	data, err := json.Marshal(tx)
+	if err != nil {
+		return fmt.Errorf("failed to marshal transaction: %w", err)
+	}
+
+	f, err := os.OpenFile(bankFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open bank file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write transaction: %w", err)
+	}
+
+	return nil
+}
+
+// LoadAll reads every transaction from the ledger file
+func LoadAll(bankFile string) ([]models.BankTransaction, error) {
+	data, err := os.ReadFile(bankFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read bank file: %w", err)
+	}
+
+	var txs []models.BankTransaction
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		if len(line) == 0 {
+			continue
+		}
+
+		var tx models.BankTransaction
+		if err := json.Unmarshal(line, &tx); err != nil {
+			continue // Skip malformed lines
+		}
+		txs = append(txs, tx)
+	}
+
+	return txs, nil
+}
+
+// Balance returns the number of minutes left in the bank
+func Balance(txs []models.BankTransaction) int {
+	total := 0
+	for _, tx := range txs {
+		total += tx.Minutes
+	}
+	return total
+}
+
+// DrawnSince returns the minutes automatically drawn from the bank at or
+// after the given time
+func DrawnSince(txs []models.BankTransaction, since time.Time) int {
+	total := 0
+	for _, tx := range txs {
+		if !tx.Auto || tx.Minutes >= 0 {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, tx.Time)
+		if err != nil || t.Before(since) {
+			continue
+		}
+		total -= tx.Minutes
+	}
+	return total
+}
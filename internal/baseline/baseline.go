@@ -0,0 +1,94 @@
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/austincgause/gametrak/internal/models"
+)
+
+// Load reads the imported baselines. A missing file means none.
+func Load(baselinesFile string) ([]models.Baseline, error) {
+	data, err := os.ReadFile(baselinesFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read baselines file: %w", err)
+	}
+
+	var baselines []models.Baseline
+	if err := json.Unmarshal(data, &baselines); err != nil {
+		return nil, fmt.Errorf("failed to parse baselines file: %w", err)
+	}
+	return baselines, nil
+}
+
+// Save replaces the baselines file
+func Save(baselinesFile string, baselines []models.Baseline) error {
+	if err := os.MkdirAll(filepath.Dir(baselinesFile), 0755); err != nil {
+		return fmt.Errorf("failed to create baselines directory: %w", err)
+	}
+
+	sort.Slice(baselines, func(i, j int) bool {
+		if baselines[i].Source != baselines[j].Source {
+			return baselines[i].Source < baselines[j].Source
+		}
+		return baselines[i].Game < baselines[j].Game
+	})
+
+	data, err := json.MarshalIndent(baselines, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal baselines: %w", err)
+	}
+
+	tmp := baselinesFile + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baselines file: %w", err)
+	}
+	if err := os.Rename(tmp, baselinesFile); err != nil {
+		return fmt.Errorf("failed to replace baselines file: %w", err)
+	}
+	return nil
+}
+
+// Replace swaps every baseline from source for the imported ones, leaving
+// other sources untouched, so re-running an import never double counts
+func Replace(baselines []models.Baseline, source string, imported []models.Baseline) []models.Baseline {
+	var result []models.Baseline
+	for _, b := range baselines {
+		if b.Source != source {
+			result = append(result, b)
+		}
+	}
+	return append(result, imported...)
+}
+
+// Totals returns baseline seconds per game for games matching the filter, a
+// case-insensitive substring as used by stats. An empty filter matches all.
+// A game imported from several launchers takes its largest baseline, since
+// each launcher's total may already include the same playtime.
+func Totals(baselines []models.Baseline, gameFilter string) map[string]int64 {
+	totals := make(map[string]int64)
+	for _, b := range baselines {
+		if gameFilter != "" && !strings.Contains(strings.ToLower(b.Game), strings.ToLower(gameFilter)) {
+			continue
+		}
+		totals[b.Game] = max(totals[b.Game], b.Seconds)
+	}
+	return totals
+}
+
+// LoggedSeconds sums the session log per game, for subtracting time
+// gametrak already tracked from an imported total
+func LoggedSeconds(sessions []models.SessionLog) map[string]int64 {
+	logged := make(map[string]int64)
+	for _, s := range sessions {
+		logged[s.Game] += s.DurationSeconds
+	}
+	return logged
+}
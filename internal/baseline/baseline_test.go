@@ -0,0 +1,45 @@
+package baseline
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/austincgause/gametrak/internal/models"
+)
+
+func TestTotals(t *testing.T) {
+	baselines := []models.Baseline{
+		{Game: "Hades", Source: "steam", Seconds: 3600},
+		{Game: "Hades", Source: "heroic", Seconds: 5400},
+		{Game: "Factorio", Source: "steam", Seconds: 600},
+		{Game: "Celeste", Source: "lutris", Seconds: 1200},
+	}
+
+	for filter, want := range map[string]map[string]int64{
+		"":      {"Hades": 5400, "Factorio": 600, "Celeste": 1200},
+		"hades": {"Hades": 5400},
+		"E":     {"Hades": 5400, "Celeste": 1200},
+		"doom":  {},
+	} {
+		if got := Totals(baselines, filter); !reflect.DeepEqual(got, want) {
+			t.Errorf("Totals(%q) = %v, want %v", filter, got, want)
+		}
+	}
+}
+
+func TestReplace(t *testing.T) {
+	existing := []models.Baseline{
+		{Game: "Hades", Source: "steam", Seconds: 3600},
+		{Game: "Portal 2", Source: "steam", Seconds: 600},
+		{Game: "Hades", Source: "heroic", Seconds: 5400},
+	}
+	imported := []models.Baseline{{Game: "Hades", Source: "steam", Seconds: 7200}}
+
+	want := []models.Baseline{
+		{Game: "Hades", Source: "heroic", Seconds: 5400},
+		{Game: "Hades", Source: "steam", Seconds: 7200},
+	}
+	if got := Replace(existing, "steam", imported); !reflect.DeepEqual(got, want) {
+		t.Errorf("Replace = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,245 @@
+package budget
+
+import (
+	"time"
+
+	"github.com/austincgause/gametrak/internal/models"
+	"github.com/austincgause/gametrak/internal/utility"
+)
+
+// Periods a limit can apply to
+const (
+	Daily  = "daily"
+	Weekly = "weekly"
+)
+
+// DefaultWarnAt is used when no warning thresholds are configured
+var DefaultWarnAt = []int{75, 100}
+
+// Status describes how much of a single limit has been used
+type Status struct {
+	Game   *models.Game // nil for the global budget
+	Period string
+	Start  time.Time // beginning of the current period
+	Used   time.Duration
+	Limit  time.Duration
+}
+
+// Name returns the game's display name, or "All games" for the global budget
+func (s Status) Name() string {
+	if s.Game == nil {
+		return "All games"
+	}
+	return s.Game.DisplayName()
+}
+
+func (s Status) scope() string {
+	if s.Game == nil {
+		return ""
+	}
+	return s.Game.Class
+}
+
+// Percent returns the share of the limit used, as a whole percentage
+func (s Status) Percent() int {
+	if s.Limit <= 0 {
+		return 0
+	}
+	return int(s.Used * 100 / s.Limit)
+}
+
+// Exceeded reports whether the limit has been reached
+func (s Status) Exceeded() bool {
+	return s.Used >= s.Limit
+}
+
+// Applies reports whether a session with the given class counts against this limit
+func (s Status) Applies(class string, games []models.Game) bool {
+	if s.Game == nil {
+		return true
+	}
+	return BelongsTo(class, *s.Game, games)
+}
+
+// Configured reports whether any global or per-game limit is set
+func Configured(cfg models.Config) bool {
+	if cfg.Budgets.IsSet() {
+		return true
+	}
+	for _, g := range cfg.Games {
+		if g.Limits.IsSet() {
+			return true
+		}
+	}
+	return false
+}
+
+// WarnAt returns the configured warning thresholds, or the defaults
+func WarnAt(b models.Budgets) []int {
+	if len(b.WarnAt) == 0 {
+		return DefaultWarnAt
+	}
+	return b.WarnAt
+}
+
+// BelongsTo reports whether a window class is attributed to game. A class is
+// attributed to the first configured game it matches, the same way the
+// monitor picks a game when a window opens.
+func BelongsTo(class string, game models.Game, games []models.Game) bool {
+	matched, ok := utility.MatchGame(class, games)
+	return ok && matched.Class == game.Class && matched.Prefix == game.Prefix
+}
+
+// Evaluate returns a Status for every configured limit, counting logged
+// sessions and the still-running active sessions up to now. Extra is added
+// to every daily limit, global and per-game, and comes from the time bank.
+func Evaluate(cfg models.Config, logs []models.SessionLog, active []models.Session, now time.Time, extra time.Duration) []Status {
+	var statuses []Status
+
+	all := func(string) bool { return true }
+	statuses = append(statuses, evaluateLimits(nil, cfg.Budgets.Limits, all, logs, active, now)...)
+
+	for i := range cfg.Games {
+		game := &cfg.Games[i]
+		if !game.Limits.IsSet() {
+			continue
+		}
+		match := func(class string) bool { return BelongsTo(class, *game, cfg.Games) }
+		statuses = append(statuses, evaluateLimits(game, game.Limits, match, logs, active, now)...)
+	}
+
+	for i := range statuses {
+		if statuses[i].Period == Daily {
+			statuses[i].Limit += extra
+		}
+	}
+	return statuses
+}
+
+func evaluateLimits(game *models.Game, limits models.Limits, match func(string) bool,
+	logs []models.SessionLog, active []models.Session, now time.Time) []Status {
+	var statuses []Status
+
+	if limits.DailyMins > 0 {
+		start := utility.StartOfDay(now)
+		statuses = append(statuses, Status{
+			Game:   game,
+			Period: Daily,
+			Start:  start,
+			Used:   Tally(logs, active, start, now, match),
+			Limit:  time.Duration(limits.DailyMins) * time.Minute,
+		})
+	}
+
+	if limits.WeeklyMins > 0 {
+		start := utility.StartOfWeek(now)
+		statuses = append(statuses, Status{
+			Game:   game,
+			Period: Weekly,
+			Start:  start,
+			Used:   Tally(logs, active, start, now, match),
+			Limit:  time.Duration(limits.WeeklyMins) * time.Minute,
+		})
+	}
+
+	return statuses
+}
+
+// Tally sums the playtime between from and to for sessions whose class
+// satisfies match. Sessions crossing the boundaries only count their overlap.
+func Tally(logs []models.SessionLog, active []models.Session, from, to time.Time, match func(class string) bool) time.Duration {
+	var total time.Duration
+
+	for _, s := range logs {
+		if !match(s.Class) {
+			continue
+		}
+		start, err := time.Parse(time.RFC3339, s.Start)
+		if err != nil {
+			continue
+		}
+		end, err := time.Parse(time.RFC3339, s.End)
+		if err != nil {
+			end = start.Add(time.Duration(s.DurationSeconds) * time.Second)
+		}
+		total += overlap(start, end, from, to)
+	}
+
+	for _, s := range active {
+		if !match(s.Class) {
+			continue
+		}
+		total += overlap(s.StartTime, to, from, to)
+	}
+
+	return total
+}
+
+func overlap(start, end, from, to time.Time) time.Duration {
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// Tracker remembers which warning thresholds have fired so that each one is
+// only sent once per period. Only the current period of each limit is kept,
+// so a long-running monitor doesn't accumulate old ones.
+type Tracker struct {
+	warned map[string]*periodWarnings
+}
+
+// periodWarnings are the thresholds sent for one period of a limit
+type periodWarnings struct {
+	start time.Time
+	sent  map[int]bool
+}
+
+// NewTracker creates an empty Tracker
+func NewTracker() *Tracker {
+	return &Tracker{warned: make(map[string]*periodWarnings)}
+}
+
+// Crossed returns the highest threshold in warnAt that the status has reached
+// without a warning being sent yet, and marks every reached threshold as sent.
+// It returns 0 when there is nothing new to report.
+func (t *Tracker) Crossed(s Status, warnAt []int) int {
+	w := t.period(s)
+	highest := 0
+	for _, pct := range warnAt {
+		if s.Percent() < pct || w.sent[pct] {
+			continue
+		}
+		w.sent[pct] = true
+		if pct > highest {
+			highest = pct
+		}
+	}
+	return highest
+}
+
+// Seed marks the thresholds each status has already reached as sent, so a
+// restarted monitor doesn't repeat warnings given earlier in the period
+func (t *Tracker) Seed(statuses []Status, warnAt []int) {
+	for _, s := range statuses {
+		t.Crossed(s, warnAt)
+	}
+}
+
+// period returns the warnings sent for the status's current period,
+// forgetting those of an earlier one
+func (t *Tracker) period(s Status) *periodWarnings {
+	key := s.scope() + "|" + s.Period
+	w := t.warned[key]
+	if w == nil || !w.start.Equal(s.Start) {
+		w = &periodWarnings{start: s.Start, sent: make(map[int]bool)}
+		t.warned[key] = w
+	}
+	return w
+}
@@ -0,0 +1,129 @@
+package budget
+
+import (
+	"testing"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/models"
+)
+
+func logged(class string, start time.Time, d time.Duration) models.SessionLog {
+	return models.SessionLog{
+		Class:           class,
+		Start:           start.Format(time.RFC3339),
+		End:             start.Add(d).Format(time.RFC3339),
+		DurationSeconds: int64(d.Seconds()),
+	}
+}
+
+func TestTally(t *testing.T) {
+	day := time.Date(2025, 3, 5, 0, 0, 0, 0, time.Local)
+	now := day.Add(20 * time.Hour)
+	all := func(string) bool { return true }
+	only := func(class string) func(string) bool {
+		return func(c string) bool { return c == class }
+	}
+
+	noEnd := logged("factorio", day.Add(10*time.Hour), time.Hour)
+	noEnd.End = ""
+
+	for _, tc := range []struct {
+		name   string
+		logs   []models.SessionLog
+		active []models.Session
+		match  func(string) bool
+		want   time.Duration
+	}{
+		{"inside the period", []models.SessionLog{logged("factorio", day.Add(9*time.Hour), 90*time.Minute)}, nil, all, 90 * time.Minute},
+		{"crossing midnight", []models.SessionLog{logged("factorio", day.Add(-30*time.Minute), time.Hour)}, nil, all, 30 * time.Minute},
+		{"before the period", []models.SessionLog{logged("factorio", day.Add(-2*time.Hour), time.Hour)}, nil, all, 0},
+		{"end from duration", []models.SessionLog{noEnd}, nil, all, time.Hour},
+		{"invalid start", []models.SessionLog{{Class: "factorio", Start: "soon", DurationSeconds: 3600}}, nil, all, 0},
+		{"active session", nil, []models.Session{{Class: "factorio", StartTime: now.Add(-15 * time.Minute)}}, all, 15 * time.Minute},
+		{"active since yesterday", nil, []models.Session{{Class: "factorio", StartTime: day.Add(-time.Hour)}}, all, 20 * time.Hour},
+		{"filtered by class",
+			[]models.SessionLog{logged("factorio", day.Add(time.Hour), time.Hour), logged("hades.exe", day.Add(3*time.Hour), 2*time.Hour)},
+			[]models.Session{{Class: "hades.exe", StartTime: now.Add(-10 * time.Minute)}},
+			only("hades.exe"), 2*time.Hour + 10*time.Minute},
+	} {
+		if got := Tally(tc.logs, tc.active, day, now, tc.match); got != tc.want {
+			t.Errorf("%s: Tally = %s, want %s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	// Wednesday evening; Monday is earlier in the week, which starts on Sunday
+	now := time.Date(2025, 3, 5, 20, 0, 0, 0, time.Local)
+	monday := time.Date(2025, 3, 3, 18, 0, 0, 0, time.Local)
+
+	cfg := models.Config{
+		Budgets: models.Budgets{Limits: models.Limits{DailyMins: 150, WeeklyMins: 600}},
+		Games: []models.Game{
+			{Class: "factorio", Name: "Factorio", Limits: models.Limits{DailyMins: 60}},
+			{Class: "steam_app_", Prefix: true, Name: "Steam"},
+			{Class: "hades.exe", Name: "Hades", Limits: models.Limits{WeeklyMins: 180}},
+		},
+	}
+	logs := []models.SessionLog{
+		logged("factorio", now.Add(-3*time.Hour), 45*time.Minute),
+		logged("hades.exe", now.Add(-2*time.Hour), time.Hour),
+		logged("hades.exe", monday, 2*time.Hour),
+		logged("steam_app_620", monday, time.Hour),
+	}
+	active := []models.Session{{Class: "factorio", StartTime: now.Add(-30 * time.Minute)}}
+
+	statuses := Evaluate(cfg, logs, active, now, 10*time.Minute)
+
+	want := []struct {
+		name   string
+		period string
+		used   time.Duration
+		limit  time.Duration
+	}{
+		// The bank's extension applies to every daily limit
+		{"All games", Daily, 2*time.Hour + 15*time.Minute, 160 * time.Minute},
+		{"All games", Weekly, 5*time.Hour + 15*time.Minute, 600 * time.Minute},
+		{"Factorio", Daily, 75 * time.Minute, 70 * time.Minute},
+		{"Hades", Weekly, 3 * time.Hour, 180 * time.Minute},
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("got %d statuses, want %d: %+v", len(statuses), len(want), statuses)
+	}
+	for i, w := range want {
+		st := statuses[i]
+		if st.Name() != w.name || st.Period != w.period || st.Used != w.used || st.Limit != w.limit {
+			t.Errorf("status %d = %s %s %s/%s, want %s %s %s/%s", i,
+				st.Name(), st.Period, st.Used, st.Limit, w.name, w.period, w.used, w.limit)
+		}
+	}
+
+	if !statuses[2].Exceeded() || statuses[0].Exceeded() {
+		t.Error("of the daily limits, only Factorio's should be exceeded")
+	}
+	if !statuses[3].Exceeded() || statuses[3].Percent() != 100 {
+		t.Errorf("Hades weekly = %d%%, want exactly exceeded", statuses[3].Percent())
+	}
+}
+
+func TestBelongsTo(t *testing.T) {
+	games := []models.Game{
+		{Class: "steam_app_620", Name: "Portal 2"},
+		{Class: "steam_app_", Prefix: true, Name: "Steam"},
+	}
+	for _, tc := range []struct {
+		class string
+		game  int
+		want  bool
+	}{
+		{"steam_app_620", 0, true},
+		// An exact match earlier in the list takes the class from the prefix
+		{"steam_app_620", 1, false},
+		{"steam_app_1145360", 1, true},
+		{"factorio", 1, false},
+	} {
+		if got := BelongsTo(tc.class, games[tc.game], games); got != tc.want {
+			t.Errorf("BelongsTo(%s, %s) = %v, want %v", tc.class, games[tc.game].Name, got, tc.want)
+		}
+	}
+}
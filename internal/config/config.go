@@ -23,6 +23,20 @@ func Load(cfg *models.Config) error {
 	if cfg.Settings.HyprlandConf == "" {
 		cfg.Settings.HyprlandConf = DefaultHyprConf
 	}
+	if cfg.Settings.ViolationsFile == "" {
+		cfg.Settings.ViolationsFile = DefaultViolations
+	}
+	if cfg.Settings.BankFile == "" {
+		cfg.Settings.BankFile = DefaultBank
+	}
+	if cfg.Settings.BaselinesFile == "" {
+		cfg.Settings.BaselinesFile = DefaultBaselines
+	}
+	// Configs written before milestones existed get the defaults; one that
+	// sets the section, even to nothing, is left as it is
+	if !viper.IsSet("milestones") {
+		cfg.Milestones = DefaultMilestones()
+	}
 
 	return nil
 }
@@ -68,6 +82,35 @@ func AddGame(game models.Game) error {
 	return Save(&cfg)
 }
 
+// AddGames adds several games to the config file at once, skipping any
+// whose class is already configured. It returns the games that were added.
+func AddGames(games []models.Game) ([]models.Game, error) {
+	var cfg models.Config
+	if err := Load(&cfg); err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(cfg.Games))
+	for _, g := range cfg.Games {
+		existing[g.Class] = true
+	}
+
+	var added []models.Game
+	for _, g := range games {
+		if existing[g.Class] {
+			continue
+		}
+		existing[g.Class] = true
+		cfg.Games = append(cfg.Games, g)
+		added = append(added, g)
+	}
+
+	if len(added) == 0 {
+		return nil, nil
+	}
+	return added, Save(&cfg)
+}
+
 // Save writes the configuration to the config file
 func Save(cfg *models.Config) error {
 	data, err := yaml.Marshal(cfg)
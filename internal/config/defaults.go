@@ -10,9 +10,32 @@ import (
 var (
 	DefaultConfigDir  = filepath.Join(xdg.ConfigHome, "gametrak")
 	DefaultDataDir    = filepath.Join(xdg.DataHome, "gametrak")
+	DefaultRuntimeDir = filepath.Join(xdg.RuntimeDir, "gametrak")
+	DefaultStateDir   = filepath.Join(xdg.StateHome, "gametrak")
+	DefaultCacheDir   = filepath.Join(xdg.CacheHome, "gametrak")
 	DefaultConfigFile = filepath.Join(DefaultConfigDir, "config.yaml")
 	DefaultSessions   = filepath.Join(DefaultDataDir, "sessions.jsonl")
 	DefaultHyprConf   = filepath.Join(DefaultConfigDir, "games.conf")
+	DefaultViolations = filepath.Join(DefaultDataDir, "violations.jsonl")
+	DefaultBank       = filepath.Join(DefaultDataDir, "bank.jsonl")
+	DefaultBaselines  = filepath.Join(DefaultDataDir, "baselines.json")
+	DefaultJournal    = filepath.Join(DefaultDataDir, "journal.json")
+	DefaultStateFile  = filepath.Join(DefaultRuntimeDir, "state.json")
+	DefaultControl    = filepath.Join(DefaultRuntimeDir, "control.sock")
+	DefaultLogFile    = filepath.Join(DefaultStateDir, "gametrak.log")
+	DefaultTimers     = filepath.Join(DefaultStateDir, "timers.json")
+	DefaultDiscovered = filepath.Join(DefaultStateDir, "discovered.json")
+	DefaultSteamCache = filepath.Join(DefaultCacheDir, "steam_apps.json")
+)
+
+// Defaults for optional integrations, applied when they are enabled
+const (
+	DefaultMetricsListen   = "127.0.0.1:9273"
+	DefaultMQTTBroker      = "localhost:1883"
+	DefaultMQTTTopic       = "gametrak"
+	DefaultDiscoveryPrefix = "homeassistant"
+	DefaultAWURL           = "http://localhost:5600"
+	DefaultAWBucket        = "gametrak"
 )
 
 // DefaultGames returns the default game patterns
@@ -38,10 +61,19 @@ func DefaultSettings() models.Settings {
 	}
 }
 
+// DefaultMilestones returns the default milestone thresholds
+func DefaultMilestones() models.Milestones {
+	return models.Milestones{
+		Hours:          []int{10, 50, 100},
+		LongestSession: true,
+	}
+}
+
 // DefaultConfig returns a complete default configuration
 func DefaultConfig() models.Config {
 	return models.Config{
-		Games:    DefaultGames(),
-		Settings: DefaultSettings(),
+		Games:      DefaultGames(),
+		Settings:   DefaultSettings(),
+		Milestones: DefaultMilestones(),
 	}
 }
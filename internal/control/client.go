@@ -0,0 +1,103 @@
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// ErrNotRunning is returned when no monitor is listening on the socket
+var ErrNotRunning = errors.New("gametrak is not running")
+
+// dial connects to the control socket and sends a request
+func dial(path string, req Request) (net.Conn, error) {
+	conn, err := net.DialTimeout("unix", path, 2*time.Second)
+	if err != nil {
+		return nil, ErrNotRunning
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := writeLine(conn, data); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	return conn, nil
+}
+
+// Status asks the monitor for its current status and decodes it into v
+func Status(path string, v any) error {
+	conn, err := dial(path, Request{Command: CommandStatus})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read status: %w", err)
+	}
+	if err := checkError(line); err != nil {
+		return err
+	}
+	return json.Unmarshal(line, v)
+}
+
+// Send delivers a request that expects a plain acknowledgement
+func Send(path string, req Request) error {
+	conn, err := dial(path, req)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read reply: %w", err)
+	}
+	return checkError(line)
+}
+
+// Watch subscribes to the monitor's events and calls fn with each one as a
+// raw JSON line. It returns when the monitor goes away or fn fails.
+func Watch(path string, fn func(line []byte) error) error {
+	conn, err := dial(path, Request{Command: CommandWatch})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if err := checkError(scanner.Bytes()); err != nil {
+			return err
+		}
+		if err := fn(scanner.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("lost connection to gametrak: %w", err)
+	}
+	return nil
+}
+
+// checkError reports an error response from the server
+func checkError(line []byte) error {
+	var resp struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(line, &resp) == nil && resp.Error != "" {
+		return errors.New(resp.Error)
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/state"
+)
+
+// Commands a client can send. SessionStart and SessionEnd report a session
+// tracked by another process, such as gametrak run, so the monitor doesn't
+// track the same game again.
+const (
+	CommandStatus       = "status"
+	CommandWatch        = "watch"
+	CommandSessionStart = "session_start"
+	CommandSessionEnd   = "session_end"
+)
+
+// subscriberBuffer is how many events a watcher may fall behind before
+// further events are dropped for it
+const subscriberBuffer = 64
+
+// Request is the single JSON line a client sends after connecting
+type Request struct {
+	Command string               `json:"command"`
+	Session *state.ActiveSession `json:"session,omitempty"`
+}
+
+// Server is the monitor's control socket. It answers status requests from
+// the latest published status and fans events out to watchers. A nil
+// *Server is valid and does nothing, so callers needn't check whether the
+// socket is running.
+type Server struct {
+	path string
+	ln   net.Listener
+
+	mu       sync.Mutex
+	status   []byte
+	subs     map[chan []byte]struct{}
+	closed   bool
+	sessions chan Request
+}
+
+// Listen creates the control socket at path. A socket left behind by a
+// crashed monitor is replaced, but one that still answers is an error.
+func Listen(path string) (*Server, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create runtime directory: %w", err)
+	}
+
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("control socket %s is in use; is gametrak already running?", path)
+	}
+	os.Remove(path)
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create control socket: %w", err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("failed to secure control socket: %w", err)
+	}
+
+	s := &Server{
+		path:     path,
+		ln:       ln,
+		status:   []byte("{}"),
+		subs:     make(map[chan []byte]struct{}),
+		sessions: make(chan Request, 16),
+	}
+	go s.serve()
+	return s, nil
+}
+
+// Sessions delivers session_start and session_end requests for the
+// monitor to apply. A nil *Server returns a nil channel, which never
+// delivers.
+func (s *Server) Sessions() <-chan Request {
+	if s == nil {
+		return nil
+	}
+	return s.sessions
+}
+
+// SetStatus replaces the value returned to status requests
+func (s *Server) SetStatus(v any) {
+	if s == nil {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	s.status = data
+	s.mu.Unlock()
+}
+
+// Broadcast sends an event to every watcher. It never blocks: a watcher
+// whose buffer is full misses the event.
+func (s *Server) Broadcast(v any) {
+	if s == nil {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subs {
+		select {
+		case ch <- data:
+		default:
+		}
+	}
+}
+
+// Close stops accepting connections, disconnects watchers and removes the
+// socket
+func (s *Server) Close() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	for ch := range s.subs {
+		close(ch)
+		delete(s.subs, ch)
+	}
+	s.mu.Unlock()
+
+	err := s.ln.Close()
+	os.Remove(s.path)
+	return err
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadBytes('\n')
+	if err != nil && len(line) == 0 {
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	var req Request
+	if err := json.Unmarshal(line, &req); err != nil {
+		writeLine(conn, errorResponse("invalid request"))
+		return
+	}
+
+	switch req.Command {
+	case CommandStatus:
+		s.mu.Lock()
+		status := s.status
+		s.mu.Unlock()
+		writeLine(conn, status)
+
+	case CommandWatch:
+		s.watch(conn, reader)
+
+	case CommandSessionStart, CommandSessionEnd:
+		if req.Session == nil || req.Session.Address == "" {
+			writeLine(conn, errorResponse("missing session"))
+			return
+		}
+		select {
+		case s.sessions <- req:
+			writeLine(conn, []byte(`{"ok":true}`))
+		default:
+			writeLine(conn, errorResponse("monitor is busy"))
+		}
+
+	default:
+		writeLine(conn, errorResponse(fmt.Sprintf("unknown command %q", req.Command)))
+	}
+}
+
+// watch streams events to a watcher until it disconnects or the server
+// closes
+func (s *Server) watch(conn net.Conn, reader io.Reader) {
+	ch := make(chan []byte, subscriberBuffer)
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.subs[ch] = struct{}{}
+	s.mu.Unlock()
+
+	defer s.unsubscribe(ch)
+
+	// Watchers don't send anything more; a read returning means they left
+	gone := make(chan struct{})
+	go func() {
+		io.Copy(io.Discard, reader)
+		close(gone)
+	}()
+
+	for {
+		select {
+		case data, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := writeLine(conn, data); err != nil {
+				return
+			}
+		case <-gone:
+			return
+		}
+	}
+}
+
+func (s *Server) unsubscribe(ch chan []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subs[ch]; ok {
+		delete(s.subs, ch)
+		close(ch)
+	}
+}
+
+func writeLine(w io.Writer, data []byte) error {
+	_, err := w.Write(append(data, '\n'))
+	return err
+}
+
+func errorResponse(msg string) []byte {
+	data, _ := json.Marshal(map[string]string{"error": msg})
+	return data
+}
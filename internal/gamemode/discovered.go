@@ -0,0 +1,107 @@
+package gamemode
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// nonGames are executables that register with GameMode, or sit above the
+// game in its process tree, without being games themselves
+var nonGames = map[string]bool{
+	"steam":                true,
+	"steamwebhelper":       true,
+	"steam-runtime-launch": true,
+	"reaper":               true,
+	"pressure-vessel-wrap": true,
+	"pv-bwrap":             true,
+	"srt-bwrap":            true,
+	"bwrap":                true,
+	"gamescope":            true,
+	"gamemoderun":          true,
+	"wine":                 true,
+	"wine64":               true,
+	"wine-preloader":       true,
+	"wine64-preloader":     true,
+	"wineserver":           true,
+	"proton":               true,
+	"umu-run":              true,
+	"lutris":               true,
+	"heroic":               true,
+	"legendary":            true,
+	"gogdl":                true,
+	"bottles":              true,
+	"python":               true,
+	"python3":              true,
+	"sh":                   true,
+	"bash":                 true,
+	"start.exe":            true,
+	"explorer.exe":         true,
+	"services.exe":         true,
+	"winedevice.exe":       true,
+	"plugplay.exe":         true,
+	"rpcss.exe":            true,
+	"conhost.exe":          true,
+}
+
+// IsGame reports whether an executable name could be a game rather than a
+// launcher, runtime or shell
+func IsGame(class string) bool {
+	return !nonGames[strings.ToLower(class)]
+}
+
+// Discovery is an unconfigured game GameMode reported, kept until
+// gametrak add promotes it to the config
+type Discovery struct {
+	Class string `json:"class"`
+	Name  string `json:"name,omitempty"`
+}
+
+// LoadDiscovered reads the games discovered through GameMode that haven't
+// been added to the config. A missing file means none.
+func LoadDiscovered(file string) ([]Discovery, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read discovered games: %w", err)
+	}
+
+	var games []Discovery
+	if err := json.Unmarshal(data, &games); err != nil {
+		return nil, fmt.Errorf("failed to parse discovered games: %w", err)
+	}
+	return games, nil
+}
+
+// SaveDiscovered replaces the discovered games file, removing it once the
+// list is empty
+func SaveDiscovered(file string, games []Discovery) error {
+	if len(games) == 0 {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove discovered games: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(games, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal discovered games: %w", err)
+	}
+
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write discovered games: %w", err)
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		return fmt.Errorf("failed to replace discovered games: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package gamemode
+
+import (
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	busName    = "com.feralinteractive.GameMode"
+	objectPath = dbus.ObjectPath("/com/feralinteractive/GameMode")
+	iface      = "com.feralinteractive.GameMode"
+)
+
+// Event is a game registering with or leaving GameMode
+type Event struct {
+	PID        int
+	Registered bool
+}
+
+// Watcher delivers GameMode registrations from the session bus
+type Watcher struct {
+	conn    *dbus.Conn
+	signals chan *dbus.Signal
+	events  chan Event
+}
+
+// Watch subscribes to GameMode's GameRegistered and GameUnregistered
+// signals. Games already registered are reported first.
+func Watch() (*Watcher, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to session bus: %w", err)
+	}
+
+	err = conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(objectPath),
+		dbus.WithMatchInterface(iface),
+	)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to subscribe to GameMode: %w", err)
+	}
+
+	w := &Watcher{
+		conn:    conn,
+		signals: make(chan *dbus.Signal, 16),
+		events:  make(chan Event, 16),
+	}
+	conn.Signal(w.signals)
+
+	// Games registered before we subscribed. Older GameMode versions lack
+	// ListGames, and GameMode may not be running yet; either way the
+	// signals will catch later games.
+	var games []struct {
+		PID  int32
+		Path dbus.ObjectPath
+	}
+	existing := conn.Object(busName, objectPath).Call(iface+".ListGames", 0).Store(&games)
+
+	go func() {
+		if existing == nil {
+			for _, g := range games {
+				w.events <- Event{PID: int(g.PID), Registered: true}
+			}
+		}
+		w.run()
+	}()
+
+	return w, nil
+}
+
+// Events returns the channel registrations are delivered on. It is closed
+// when the watcher is closed or the bus connection drops.
+func (w *Watcher) Events() <-chan Event {
+	return w.events
+}
+
+// Close disconnects from the session bus
+func (w *Watcher) Close() error {
+	return w.conn.Close()
+}
+
+func (w *Watcher) run() {
+	defer close(w.events)
+
+	for sig := range w.signals {
+		var registered bool
+		switch sig.Name {
+		case iface + ".GameRegistered":
+			registered = true
+		case iface + ".GameUnregistered":
+			registered = false
+		default:
+			continue
+		}
+
+		if len(sig.Body) == 0 {
+			continue
+		}
+		pid, ok := sig.Body[0].(int32)
+		if !ok {
+			continue
+		}
+		w.events <- Event{PID: int(pid), Registered: registered}
+	}
+}
@@ -0,0 +1,39 @@
+package gamescope
+
+import (
+	"strings"
+
+	"github.com/austincgause/gametrak/internal/process"
+	"github.com/austincgause/gametrak/internal/steam"
+)
+
+// Class is the window class every game running in gamescope shares
+const Class = "gamescope"
+
+// IsGamescope reports whether a window class belongs to gamescope
+func IsGamescope(class string) bool {
+	return strings.EqualFold(class, Class)
+}
+
+// Candidates returns window classes that could identify the game running
+// under the gamescope process pid, most specific first: steam_app_<id> for
+// processes started by Steam with a SteamAppId, then the executable name
+// of every descendant, nearest first.
+func Candidates(pid int) []string {
+	var apps, exes []string
+	seen := make(map[string]bool)
+	for _, p := range process.Descendants(pid) {
+		for _, class := range process.Classes(p) {
+			if seen[class] {
+				continue
+			}
+			seen[class] = true
+			if strings.HasPrefix(class, steam.AppClassPrefix) {
+				apps = append(apps, class)
+			} else {
+				exes = append(exes, class)
+			}
+		}
+	}
+	return append(apps, exes...)
+}
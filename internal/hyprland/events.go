@@ -6,6 +6,7 @@ import "strings"
 const (
 	EventOpenWindow  = "openwindow"
 	EventCloseWindow = "closewindow"
+	EventWindowTitle = "windowtitlev2"
 )
 
 // OpenWindowEvent represents a parsed openwindow event
@@ -21,6 +22,12 @@ type CloseWindowEvent struct {
 	Address string
 }
 
+// WindowTitleEvent represents a parsed windowtitlev2 event
+type WindowTitleEvent struct {
+	Address string
+	Title   string
+}
+
 // ParseEvent extracts the event type and data from a raw event line
 func ParseEvent(line string) (eventType string, data string, ok bool) {
 	parts := strings.SplitN(line, ">>", 2)
@@ -60,3 +67,13 @@ func ParseCloseWindow(data string) (CloseWindowEvent, bool) {
 	}
 	return CloseWindowEvent{Address: address}, true
 }
+
+// ParseWindowTitle parses the data portion of a windowtitlev2 event.
+// Format: ADDRESS,TITLE
+func ParseWindowTitle(data string) (WindowTitleEvent, bool) {
+	parts := strings.SplitN(data, ",", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return WindowTitleEvent{}, false
+	}
+	return WindowTitleEvent{Address: parts[0], Title: parts[1]}, true
+}
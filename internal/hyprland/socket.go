@@ -2,14 +2,36 @@ package hyprland
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
 // GetSocketPath builds the Hyprland socket2 path from environment variables
 func GetSocketPath() (string, error) {
+	dir, err := instanceDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ".socket2.sock"), nil
+}
+
+// GetRequestSocketPath builds the path to Hyprland's request socket, which
+// accepts hyprctl-style commands
+func GetRequestSocketPath() (string, error) {
+	dir, err := instanceDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ".socket.sock"), nil
+}
+
+func instanceDir() (string, error) {
 	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
 	if runtimeDir == "" {
 		return "", fmt.Errorf("XDG_RUNTIME_DIR not set")
@@ -20,7 +42,7 @@ func GetSocketPath() (string, error) {
 		return "", fmt.Errorf("HYPRLAND_INSTANCE_SIGNATURE not set (is Hyprland running?)")
 	}
 
-	return filepath.Join(runtimeDir, "hypr", instanceSig, ".socket2.sock"), nil
+	return filepath.Join(runtimeDir, "hypr", instanceSig), nil
 }
 
 // Connect establishes a connection to the Hyprland event socket
@@ -51,3 +73,97 @@ func Listen(conn net.Conn, events chan<- string, errors chan<- error) {
 	}
 	close(events)
 }
+
+// Request sends a single command over the request socket and returns the reply.
+// Hyprland closes the connection after answering, so each call dials anew.
+func Request(command string) (string, error) {
+	socketPath, err := GetRequestSocketPath()
+	if err != nil {
+		return "", err
+	}
+
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to Hyprland request socket: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(command)); err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+
+	reply, err := io.ReadAll(conn)
+	if err != nil {
+		return "", fmt.Errorf("failed to read reply: %w", err)
+	}
+
+	return strings.TrimSpace(string(reply)), nil
+}
+
+// CloseWindow asks Hyprland to close the window at the given address
+func CloseWindow(address string) error {
+	if !strings.HasPrefix(address, "0x") {
+		address = "0x" + address
+	}
+
+	reply, err := Request("dispatch closewindow address:" + address)
+	if err != nil {
+		return err
+	}
+	if reply != "ok" {
+		return fmt.Errorf("closewindow %s: %s", address, reply)
+	}
+	return nil
+}
+
+// Notify shows a message in Hyprland's notification overlay. Icon is one of
+// Hyprland's icon numbers (-1 for none, 0 warning, 1 info, 2 hint, 3 error,
+// 4 confused, 5 ok).
+func Notify(icon int, timeout time.Duration, message string) error {
+	reply, err := Request(fmt.Sprintf("notify %d %d 0 %s", icon, timeout.Milliseconds(), message))
+	if err != nil {
+		return err
+	}
+	if reply != "ok" {
+		return fmt.Errorf("notify: %s", reply)
+	}
+	return nil
+}
+
+// Client is a window as reported by the clients query
+type Client struct {
+	Address string `json:"address"`
+	Class   string `json:"class"`
+	Title   string `json:"title"`
+	PID     int    `json:"pid"`
+}
+
+// Clients lists the open windows
+func Clients() ([]Client, error) {
+	reply, err := Request("j/clients")
+	if err != nil {
+		return nil, err
+	}
+
+	var clients []Client
+	if err := json.Unmarshal([]byte(reply), &clients); err != nil {
+		return nil, fmt.Errorf("failed to parse clients: %w", err)
+	}
+	return clients, nil
+}
+
+// WindowPID returns the process that owns the window at the given address
+func WindowPID(address string) (int, error) {
+	clients, err := Clients()
+	if err != nil {
+		return 0, err
+	}
+
+	address = strings.TrimPrefix(address, "0x")
+	for _, c := range clients {
+		if strings.TrimPrefix(c.Address, "0x") == address {
+			return c.PID, nil
+		}
+	}
+	return 0, fmt.Errorf("no window with address %s", address)
+}
@@ -0,0 +1,121 @@
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/models"
+)
+
+// MaxEntries bounds the journal; the oldest changes are forgotten first
+const MaxEntries = 50
+
+// Load reads the journal, oldest change first. A missing file means none.
+func Load(journalFile string) ([]models.JournalEntry, error) {
+	data, err := os.ReadFile(journalFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	var entries []models.JournalEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse journal: %w", err)
+	}
+	return entries, nil
+}
+
+// Save replaces the journal, keeping only the newest MaxEntries changes
+func Save(journalFile string, entries []models.JournalEntry) error {
+	if err := os.MkdirAll(filepath.Dir(journalFile), 0755); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	if len(entries) > MaxEntries {
+		entries = entries[len(entries)-MaxEntries:]
+	}
+	if entries == nil {
+		entries = []models.JournalEntry{}
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal: %w", err)
+	}
+
+	tmp := journalFile + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	if err := os.Rename(tmp, journalFile); err != nil {
+		return fmt.Errorf("failed to replace journal: %w", err)
+	}
+	return nil
+}
+
+// Record adds a change to the journal. The journal has no lock of its own;
+// callers hold the sessions file's lock, as every change it records does.
+func Record(journalFile string, entry models.JournalEntry) error {
+	entries, err := Load(journalFile)
+	if err != nil {
+		return err
+	}
+	if entry.Time == "" {
+		entry.Time = time.Now().Format(time.RFC3339)
+	}
+	return Save(journalFile, append(entries, entry))
+}
+
+// Revert undoes a change: sessions it added or edited are taken out and
+// the before-images put back. It refuses if a session has been changed
+// again since, so later edits are never silently lost.
+func Revert(sessions []models.SessionLog, entry models.JournalEntry) ([]models.SessionLog, error) {
+	for _, after := range entry.After {
+		i := indexOf(sessions, after.ID)
+		if i < 0 {
+			return nil, fmt.Errorf("session %s no longer exists", after.ID)
+		}
+		if !reflect.DeepEqual(sessions[i], after) {
+			return nil, fmt.Errorf("session %s has changed since", after.ID)
+		}
+		sessions = append(sessions[:i], sessions[i+1:]...)
+	}
+
+	for _, before := range entry.Before {
+		if indexOf(sessions, before.ID) >= 0 {
+			return nil, fmt.Errorf("session %s already exists", before.ID)
+		}
+		sessions = insertByStart(sessions, before)
+	}
+	return sessions, nil
+}
+
+func indexOf(sessions []models.SessionLog, id string) int {
+	for i, s := range sessions {
+		if s.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// insertByStart puts a session back before the first one that started
+// after it, which is where it was unless the log was out of order
+func insertByStart(sessions []models.SessionLog, s models.SessionLog) []models.SessionLog {
+	start, err := time.Parse(time.RFC3339, s.Start)
+	if err != nil {
+		return append(sessions, s)
+	}
+	for i, other := range sessions {
+		if t, err := time.Parse(time.RFC3339, other.Start); err == nil && t.After(start) {
+			return append(sessions[:i], append([]models.SessionLog{s}, sessions[i:]...)...)
+		}
+	}
+	return append(sessions, s)
+}
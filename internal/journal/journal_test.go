@@ -0,0 +1,105 @@
+package journal
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/austincgause/gametrak/internal/models"
+)
+
+func entry(id, game, start string) models.SessionLog {
+	return models.SessionLog{ID: id, Game: game, Class: game, Start: start, DurationSeconds: 600}
+}
+
+func ids(sessions []models.SessionLog) string {
+	var s string
+	for _, session := range sessions {
+		s += session.ID + " "
+	}
+	return s
+}
+
+func TestRevert(t *testing.T) {
+	first := entry("a1", "Hades", "2025-01-01T10:00:00Z")
+	second := entry("b2", "Factorio", "2025-01-02T10:00:00Z")
+	third := entry("c3", "Celeste", "2025-01-03T10:00:00Z")
+	edited := second
+	edited.DurationSeconds = 1200
+
+	log := func(sessions ...models.SessionLog) []models.SessionLog { return sessions }
+
+	for _, tc := range []struct {
+		name     string
+		sessions []models.SessionLog
+		change   models.JournalEntry
+		want     string
+		err      bool
+	}{
+		{"added", log(first, second, third),
+			models.JournalEntry{After: log(third)}, "a1 b2 ", false},
+		{"deleted goes back by start", log(first, third),
+			models.JournalEntry{Before: log(second)}, "a1 b2 c3 ", false},
+		{"edited", log(first, edited, third),
+			models.JournalEntry{Before: log(second), After: log(edited)}, "a1 b2 c3 ", false},
+		{"changed since", log(first, second, third),
+			models.JournalEntry{Before: log(second), After: log(edited)}, "", true},
+		{"deleted since", log(first, third),
+			models.JournalEntry{Before: log(second), After: log(edited)}, "", true},
+		{"already back", log(first, second, third),
+			models.JournalEntry{Before: log(second)}, "", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Revert(tc.sessions, tc.change)
+			if tc.err {
+				if err == nil {
+					t.Errorf("Revert = %s, want an error", ids(got))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ids(got) != tc.want {
+				t.Errorf("Revert = %s, want %s", ids(got), tc.want)
+			}
+		})
+	}
+}
+
+func TestRevertRestoresEdit(t *testing.T) {
+	before := entry("b2", "Factorio", "2025-01-02T10:00:00Z")
+	after := before
+	after.Game = "Factorio: Space Age"
+
+	got, err := Revert([]models.SessionLog{after}, models.JournalEntry{Before: []models.SessionLog{before}, After: []models.SessionLog{after}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != before {
+		t.Errorf("Revert = %+v, want %+v", got, before)
+	}
+}
+
+func TestRecordKeepsNewest(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "journal.json")
+	for i := range MaxEntries + 5 {
+		if err := Record(file, models.JournalEntry{Command: "delete", Description: fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != MaxEntries {
+		t.Fatalf("journal has %d entries, want %d", len(entries), MaxEntries)
+	}
+	if entries[0].Description != "5" || entries[len(entries)-1].Description != fmt.Sprint(MaxEntries+4) {
+		t.Errorf("journal runs from %s to %s, want the newest", entries[0].Description, entries[len(entries)-1].Description)
+	}
+	if entries[0].Time == "" {
+		t.Error("recorded entry has no time")
+	}
+}
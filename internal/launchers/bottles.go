@@ -0,0 +1,64 @@
+package launchers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/adrg/xdg"
+	"gopkg.in/yaml.v3"
+)
+
+// bottlesEntry is a program in Bottles' library.yml
+type bottlesEntry struct {
+	ID     string `yaml:"id"`
+	Name   string `yaml:"name"`
+	Path   string `yaml:"path"`
+	Bottle struct {
+		Name string `yaml:"name"`
+	} `yaml:"bottle"`
+}
+
+// loadBottles reads the programs added to Bottles' library. Bottles doesn't
+// record playtime there, so none is imported.
+func loadBottles() ([]Game, error) {
+	path, ok := firstExisting(
+		filepath.Join(xdg.DataHome, "bottles", "library.yml"),
+		filepath.Join(xdg.Home, ".var", "app", "com.usebottles.bottles", "data", "bottles", "library.yml"),
+	)
+	if !ok {
+		return nil, nil
+	}
+	return readBottles(path)
+}
+
+// readBottles parses a Bottles library.yml
+func readBottles(path string) ([]Game, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Bottles library: %w", err)
+	}
+
+	var library map[string]bottlesEntry
+	if err := yaml.Unmarshal(data, &library); err != nil {
+		return nil, fmt.Errorf("failed to parse Bottles library: %w", err)
+	}
+
+	var games []Game
+	for key, e := range library {
+		if e.Name == "" {
+			continue
+		}
+		id := e.ID
+		if id == "" {
+			id = key
+		}
+		games = append(games, Game{
+			Name:   e.Name,
+			Source: Bottles,
+			ID:     id,
+			Class:  classFor(e.Path),
+		})
+	}
+	return games, nil
+}
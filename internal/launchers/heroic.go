@@ -0,0 +1,165 @@
+package launchers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/adrg/xdg"
+)
+
+// heroicDir finds Heroic's config directory, native or Flatpak
+func heroicDir() (string, bool) {
+	return firstExisting(
+		filepath.Join(xdg.ConfigHome, "heroic"),
+		filepath.Join(xdg.Home, ".var", "app", "com.heroicgameslauncher.hgl", "config", "heroic"),
+	)
+}
+
+// heroicGame is the subset of a library entry gametrak reads. Epic, GOG,
+// Amazon and sideloaded libraries share this shape.
+type heroicGame struct {
+	AppName string `json:"app_name"`
+	Title   string `json:"title"`
+	Install struct {
+		Executable  string `json:"executable"`
+		InstallPath string `json:"install_path"`
+	} `json:"install"`
+	IsInstalled bool `json:"is_installed"`
+}
+
+// heroicInstalled is an entry of legendary's installed.json
+type heroicInstalled struct {
+	Title      string `json:"title"`
+	Executable string `json:"executable"`
+}
+
+// gogInfo is the part of a GOG game's goggame-<id>.info naming its
+// executable
+type gogInfo struct {
+	PlayTasks []struct {
+		IsPrimary bool   `json:"isPrimary"`
+		Path      string `json:"path"`
+	} `json:"playTasks"`
+}
+
+// loadHeroic reads Heroic's cached libraries, installed games and playtime
+// tracking
+func loadHeroic() ([]Game, error) {
+	dir, ok := heroicDir()
+	if !ok {
+		return nil, nil
+	}
+	return readHeroic(dir)
+}
+
+// readHeroic reads the libraries under a Heroic config directory
+func readHeroic(dir string) ([]Game, error) {
+	byID := make(map[string]*Game)
+	var order []string
+	add := func(id, title string) *Game {
+		if g, ok := byID[id]; ok {
+			if g.Name == "" {
+				g.Name = title
+			}
+			return g
+		}
+		g := &Game{Name: title, Source: Heroic, ID: id}
+		byID[id] = g
+		order = append(order, id)
+		return g
+	}
+
+	libraries := []struct {
+		file string
+		key  string
+	}{
+		{filepath.Join(dir, "store_cache", "legendary_library.json"), "library"},
+		{filepath.Join(dir, "store_cache", "gog_library.json"), "games"},
+		{filepath.Join(dir, "store_cache", "nile_library.json"), "library"},
+		{filepath.Join(dir, "sideload_apps", "library.json"), "games"},
+	}
+	for _, lib := range libraries {
+		var doc map[string]json.RawMessage
+		if err := readJSON(lib.file, &doc); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		var entries []heroicGame
+		if raw, ok := doc[lib.key]; ok {
+			json.Unmarshal(raw, &entries)
+		}
+		for _, e := range entries {
+			if e.AppName == "" {
+				continue
+			}
+			g := add(e.AppName, e.Title)
+			if e.IsInstalled && e.Install.Executable != "" {
+				g.Class = classFor(e.Install.Executable)
+			}
+			if g.Class == "" && e.IsInstalled && e.Install.InstallPath != "" {
+				g.Class = gogClass(e.Install.InstallPath, e.AppName)
+			}
+		}
+	}
+
+	// Installed Epic games list their executable here even when the
+	// library cache is stale
+	var installed map[string]heroicInstalled
+	if err := readJSON(filepath.Join(dir, "legendaryConfig", "legendary", "installed.json"), &installed); err == nil {
+		for id, e := range installed {
+			g := add(id, e.Title)
+			if g.Class == "" {
+				g.Class = classFor(e.Executable)
+			}
+		}
+	}
+
+	// Heroic records minutes played per app
+	var played map[string]struct {
+		TotalPlayed float64 `json:"totalPlayed"`
+	}
+	if err := readJSON(filepath.Join(dir, "store", "timestamp.json"), &played); err == nil {
+		for id, p := range played {
+			if g, ok := byID[id]; ok {
+				g.PlaytimeSeconds = int64(p.TotalPlayed * 60)
+			}
+		}
+	}
+
+	var games []Game
+	for _, id := range order {
+		if g := byID[id]; g.Name != "" {
+			games = append(games, *g)
+		}
+	}
+	return games, nil
+}
+
+// gogClass finds a GOG game's primary executable from its info file
+func gogClass(installPath, id string) string {
+	var info gogInfo
+	if err := readJSON(filepath.Join(installPath, "goggame-"+id+".info"), &info); err != nil {
+		return ""
+	}
+	for _, task := range info.PlayTasks {
+		if task.IsPrimary {
+			return classFor(task.Path)
+		}
+	}
+	return ""
+}
+
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
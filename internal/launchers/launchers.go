@@ -0,0 +1,115 @@
+package launchers
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Supported launchers
+const (
+	Lutris  = "lutris"
+	Heroic  = "heroic"
+	Bottles = "bottles"
+)
+
+// Sources lists the supported launchers in the order they are searched
+var Sources = []string{Lutris, Heroic, Bottles}
+
+// Game is a game from a launcher's library
+type Game struct {
+	Name   string
+	Source string
+	// ID is the launcher's own identifier, such as a Lutris slug
+	ID string
+	// Class is the window class the game's executable is expected to get,
+	// or "" if the executable isn't known
+	Class string
+	// PlaytimeSeconds is the playtime the launcher recorded, if it tracks it
+	PlaytimeSeconds int64
+}
+
+// Load reads a launcher's library, sorted by name. A launcher that isn't
+// installed has an empty library.
+func Load(source string) ([]Game, error) {
+	var games []Game
+	var err error
+	switch source {
+	case Lutris:
+		games, err = loadLutris()
+	case Heroic:
+		games, err = loadHeroic()
+	case Bottles:
+		games, err = loadBottles()
+	default:
+		return nil, fmt.Errorf("unknown launcher %q (expected lutris, heroic or bottles)", source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(games, func(i, j int) bool {
+		return strings.ToLower(games[i].Name) < strings.ToLower(games[j].Name)
+	})
+	return games, nil
+}
+
+// Index maps window classes to game names across launchers
+type Index struct {
+	names map[string]string
+}
+
+// NewIndex loads every launcher's library, skipping any that fail
+func NewIndex() *Index {
+	idx := &Index{names: make(map[string]string)}
+	for _, source := range Sources {
+		games, err := Load(source)
+		if err != nil {
+			continue
+		}
+		for _, g := range games {
+			if key := strings.ToLower(g.Class); key != "" {
+				if _, exists := idx.names[key]; !exists {
+					idx.names[key] = g.Name
+				}
+			}
+		}
+	}
+	return idx
+}
+
+// Name returns the game name for a window class. Classes are compared
+// case-insensitively. A nil *Index never resolves anything.
+func (idx *Index) Name(class string) (string, bool) {
+	if idx == nil {
+		return "", false
+	}
+	name, ok := idx.names[strings.ToLower(class)]
+	return name, ok
+}
+
+// classFor derives the window class from an executable path. Wine names
+// windows after the lowercased executable, so .exe names are lowercased.
+// Windows-style paths are accepted.
+func classFor(exe string) string {
+	exe = strings.TrimSpace(exe)
+	if exe == "" {
+		return ""
+	}
+	base := filepath.Base(strings.ReplaceAll(exe, `\`, "/"))
+	if strings.EqualFold(filepath.Ext(base), ".exe") {
+		return strings.ToLower(base)
+	}
+	return base
+}
+
+// firstExisting returns the first path that exists
+func firstExisting(paths ...string) (string, bool) {
+	for _, p := range paths {
+		if exists(p) {
+			return p, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,133 @@
+package launchers
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestClassFor(t *testing.T) {
+	for exe, want := range map[string]string{
+		"/home/player/Games/factorio/bin/x64/factorio": "factorio",
+		"vvvvvv.x86_64": "vvvvvv.x86_64",
+		`C:\Program Files\Hollow Knight\Hollow Knight.EXE`: "hollow knight.exe",
+		`x64\Hades.exe`:                  "hades.exe",
+		"drive_c/Games/Game.Exe":         "game.exe",
+		"/games/Celeste/Celeste":         "Celeste",
+		"  /games/Celeste/Celeste.exe  ": "celeste.exe",
+		"":                               "",
+		"   ":                            "",
+	} {
+		if got := classFor(exe); got != want {
+			t.Errorf("classFor(%q) = %q, want %q", exe, got, want)
+		}
+	}
+}
+
+// sortedByName orders games the way Load does, for comparing libraries
+// read from maps
+func sortedByName(games []Game) []Game {
+	sort.Slice(games, func(i, j int) bool { return games[i].Name < games[j].Name })
+	return games
+}
+
+func TestReadBottles(t *testing.T) {
+	games, err := readBottles(filepath.Join("testdata", "bottles", "library.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Game{
+		{Name: "Battle.net", Source: Bottles, ID: "launcher-only", Class: "battle.net launcher.exe"},
+		{Name: "Hollow Knight", Source: Bottles, ID: "3f2a9c1e-0b7d-4c57-9d43-7a1f3b2c8e10", Class: "hollow knight.exe"},
+	}
+	if got := sortedByName(games); !reflect.DeepEqual(got, want) {
+		t.Errorf("games = %+v\nwant %+v", got, want)
+	}
+}
+
+func TestReadHeroic(t *testing.T) {
+	games, err := readHeroic(filepath.Join("testdata", "heroic"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Game{
+		// The GOG install path leads to the primary play task
+		{Name: "Celeste", Source: Heroic, ID: "1207658930", Class: "celeste.exe"},
+		// Not installed, so its executable isn't known
+		{Name: "Fortnite", Source: Heroic, ID: "Fortnite"},
+		// The library's executable wins over installed.json's
+		{Name: "Hades", Source: Heroic, ID: "Quail", Class: "hades.exe", PlaytimeSeconds: 754 * 60},
+		// Named and found only through installed.json
+		{Name: "Hogwarts Legacy", Source: Heroic, ID: "Fae", Class: "hogwartslegacy.exe"},
+		{Name: "VVVVVV", Source: Heroic, ID: "sideload-vvvvvv", Class: "vvvvvv.x86_64", PlaytimeSeconds: 5430},
+	}
+	if got := sortedByName(games); !reflect.DeepEqual(got, want) {
+		t.Errorf("games = %+v\nwant %+v", got, want)
+	}
+}
+
+func TestReadHeroicMissingFiles(t *testing.T) {
+	games, err := readHeroic(t.TempDir())
+	if err != nil || len(games) != 0 {
+		t.Errorf("empty Heroic directory = %v, %v; want no games", games, err)
+	}
+}
+
+// stubLutris replaces the sqlite3 query with fixed output
+func stubLutris(t *testing.T, out []byte) {
+	t.Helper()
+	saved := queryLutris
+	t.Cleanup(func() { queryLutris = saved })
+	queryLutris = func(string) ([]byte, error) { return out, nil }
+}
+
+func TestReadLutris(t *testing.T) {
+	out, err := os.ReadFile(filepath.Join("testdata", "lutris", "pga.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stubLutris(t, out)
+	configDirs := []string{filepath.Join("testdata", "lutris", "config")}
+	dataDirs := []string{filepath.Join("testdata", "lutris", "data")}
+
+	games, err := readLutris("pga.db", configDirs, dataDirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Game{
+		// From the main_file of a config in the data directory
+		{Name: "Celeste", Source: Lutris, ID: "celeste", Class: "Celeste", PlaytimeSeconds: 3 * 3600},
+		// From the exe of a Wine game's config
+		{Name: "Diablo II", Source: Lutris, ID: "diablo-ii", Class: "game.exe", PlaytimeSeconds: 900},
+		{Name: "Factorio", Source: Lutris, ID: "factorio", Class: "factorio", PlaytimeSeconds: 45000},
+		{Name: "Uninstalled", Source: Lutris, ID: "uninstalled", PlaytimeSeconds: 3600},
+	}
+	if got := sortedByName(games); !reflect.DeepEqual(got, want) {
+		t.Errorf("games = %+v\nwant %+v", got, want)
+	}
+}
+
+func TestReadLutrisEmpty(t *testing.T) {
+	stubLutris(t, nil)
+	games, err := readLutris("pga.db", nil, nil)
+	if err != nil || len(games) != 0 {
+		t.Errorf("empty games table = %v, %v; want no games", games, err)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	idx := &Index{names: map[string]string{"hades.exe": "Hades"}}
+	if name, ok := idx.Name("Hades.EXE"); !ok || name != "Hades" {
+		t.Errorf("Name(Hades.EXE) = %q, %v; want Hades", name, ok)
+	}
+
+	var none *Index
+	if _, ok := none.Name("hades.exe"); ok {
+		t.Error("nil index resolved a name")
+	}
+}
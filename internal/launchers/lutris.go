@@ -0,0 +1,150 @@
+package launchers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/adrg/xdg"
+	"gopkg.in/yaml.v3"
+)
+
+// lutrisDirs are the native and Flatpak data and config directories
+func lutrisDirs() (data, config []string) {
+	flatpak := filepath.Join(xdg.Home, ".var", "app", "net.lutris.Lutris")
+	data = []string{
+		filepath.Join(xdg.DataHome, "lutris"),
+		filepath.Join(flatpak, "data", "lutris"),
+	}
+	config = []string{
+		filepath.Join(xdg.ConfigHome, "lutris"),
+		filepath.Join(flatpak, "config", "lutris"),
+	}
+	return data, config
+}
+
+// lutrisRow is a row of pga.db's games table
+type lutrisRow struct {
+	Name       string  `json:"name"`
+	Slug       string  `json:"slug"`
+	Executable string  `json:"executable"`
+	ConfigPath string  `json:"configpath"`
+	Playtime   float64 `json:"playtime"`
+	Installed  int     `json:"installed"`
+}
+
+// lutrisConfig is the part of a game's YAML config naming its executable
+type lutrisConfig struct {
+	Game struct {
+		Exe      string `yaml:"exe"`
+		MainFile string `yaml:"main_file"`
+	} `yaml:"game"`
+}
+
+// loadLutris reads Lutris's pga.db through the sqlite3 command line tool,
+// which avoids linking an SQLite library into gametrak
+func loadLutris() ([]Game, error) {
+	dataDirs, configDirs := lutrisDirs()
+
+	var db string
+	for _, dir := range dataDirs {
+		if exists(filepath.Join(dir, "pga.db")) {
+			db = filepath.Join(dir, "pga.db")
+			break
+		}
+	}
+	if db == "" {
+		return nil, nil
+	}
+	return readLutris(db, configDirs, dataDirs)
+}
+
+// queryLutris returns pga.db's games table as JSON. Tests replace it to
+// avoid needing sqlite3.
+var queryLutris = func(db string) ([]byte, error) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		return nil, fmt.Errorf("reading the Lutris library requires the sqlite3 command")
+	}
+
+	out, err := exec.Command("sqlite3", "-readonly", "-json", db,
+		"SELECT name, slug, executable, configpath, playtime, installed FROM games").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", db, err)
+	}
+	return out, nil
+}
+
+// readLutris reads the games in a pga.db, taking executables from the
+// games' configs where they have one
+func readLutris(db string, configDirs, dataDirs []string) ([]Game, error) {
+	out, err := queryLutris(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []lutrisRow
+	if len(out) > 0 {
+		if err := json.Unmarshal(out, &rows); err != nil {
+			return nil, fmt.Errorf("failed to parse Lutris library: %w", err)
+		}
+	}
+
+	var games []Game
+	for _, r := range rows {
+		if r.Name == "" {
+			continue
+		}
+		exe := r.Executable
+		if cfgExe := lutrisExecutable(configDirs, dataDirs, r.ConfigPath); cfgExe != "" {
+			exe = cfgExe
+		}
+		g := Game{
+			Name:            r.Name,
+			Source:          Lutris,
+			ID:              r.Slug,
+			PlaytimeSeconds: int64(r.Playtime * 3600),
+		}
+		if r.Installed != 0 {
+			g.Class = classFor(exe)
+		}
+		games = append(games, g)
+	}
+	return games, nil
+}
+
+// lutrisExecutable reads the executable from a game's YAML config, which
+// newer Lutris versions keep in the data directory
+func lutrisExecutable(configDirs, dataDirs []string, configPath string) string {
+	if configPath == "" {
+		return ""
+	}
+
+	var paths []string
+	for _, dir := range append(configDirs, dataDirs...) {
+		paths = append(paths, filepath.Join(dir, "games", configPath+".yml"))
+	}
+	path, ok := firstExisting(paths...)
+	if !ok {
+		return ""
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var cfg lutrisConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return ""
+	}
+	if cfg.Game.Exe != "" {
+		return cfg.Game.Exe
+	}
+	return cfg.Game.MainFile
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,141 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+// ConsoleHandler writes records for someone watching the monitor in a
+// terminal: a [HH:MM:SS] timestamp, the level unless it's info, the message
+// and then any attributes as key=value.
+type ConsoleHandler struct {
+	mu    *sync.Mutex
+	w     io.Writer
+	level slog.Leveler
+	// wrap re-applies WithAttrs and WithGroup to the handler formatting
+	// the attributes
+	wrap []func(slog.Handler) slog.Handler
+}
+
+// NewConsoleHandler creates a ConsoleHandler writing to w
+func NewConsoleHandler(w io.Writer, opts *slog.HandlerOptions) *ConsoleHandler {
+	var level slog.Leveler = slog.LevelInfo
+	if opts != nil && opts.Level != nil {
+		level = opts.Level
+	}
+	return &ConsoleHandler{mu: &sync.Mutex{}, w: w, level: level}
+}
+
+// Enabled reports whether records at level are written
+func (h *ConsoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle writes a record as one line
+func (h *ConsoleHandler) Handle(ctx context.Context, r slog.Record) error {
+	var line bytes.Buffer
+	line.WriteString("[" + r.Time.Format("15:04:05") + "] ")
+	if r.Level != slog.LevelInfo {
+		line.WriteString(r.Level.String() + ": ")
+	}
+	line.WriteString(r.Message)
+
+	// The text handler quotes the attributes; its own time, level and
+	// message are dropped
+	var attrs bytes.Buffer
+	var inner slog.Handler = slog.NewTextHandler(&attrs, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey) {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	for _, wrap := range h.wrap {
+		inner = wrap(inner)
+	}
+	if err := inner.Handle(ctx, r); err != nil {
+		return err
+	}
+	if text := bytes.TrimSpace(attrs.Bytes()); len(text) > 0 {
+		line.WriteByte(' ')
+		line.Write(text)
+	}
+	line.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(line.Bytes())
+	return err
+}
+
+// WithAttrs returns a handler that adds attrs to every record
+func (h *ConsoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(inner slog.Handler) slog.Handler { return inner.WithAttrs(attrs) })
+}
+
+// WithGroup returns a handler that nests later attributes under name
+func (h *ConsoleHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(inner slog.Handler) slog.Handler { return inner.WithGroup(name) })
+}
+
+func (h *ConsoleHandler) with(wrap func(slog.Handler) slog.Handler) *ConsoleHandler {
+	c := *h
+	c.wrap = append(h.wrap[:len(h.wrap):len(h.wrap)], wrap)
+	return &c
+}
+
+// fanout sends every record to each of its handlers that accepts it
+type fanout []slog.Handler
+
+func (f fanout) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanout) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range f {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(fanout, len(f))
+	for i, h := range f {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (f fanout) WithGroup(name string) slog.Handler {
+	handlers := make(fanout, len(f))
+	for i, h := range f {
+		handlers[i] = h.WithGroup(name)
+	}
+	return handlers
+}
+
+// isTerminal reports whether w is a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	_, err := unix.IoctlGetTermios(int(f.Fd()), unix.TCGETS)
+	return err == nil
+}
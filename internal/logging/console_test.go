@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConsoleHandler(t *testing.T) {
+	at := time.Date(2025, 1, 1, 20, 5, 9, 0, time.Local)
+
+	for _, tc := range []struct {
+		name  string
+		level slog.Level
+		msg   string
+		with  func(slog.Handler) slog.Handler
+		attrs []slog.Attr
+		want  string
+	}{
+		{"plain message", slog.LevelInfo, "[Factorio] started", nil, nil,
+			"[20:05:09] [Factorio] started\n"},
+		{"attributes", slog.LevelInfo, "session logged", nil,
+			[]slog.Attr{slog.String("game", "Hades II"), slog.Int("mins", 42)},
+			`[20:05:09] session logged game="Hades II" mins=42` + "\n"},
+		{"warning", slog.LevelWarn, "lost connection", nil, []slog.Attr{slog.String("error", "EOF")},
+			"[20:05:09] WARN: lost connection error=EOF\n"},
+		{"handler attributes and groups", slog.LevelDebug, "event",
+			func(h slog.Handler) slog.Handler {
+				return h.WithAttrs([]slog.Attr{slog.String("source", "gamemode")}).WithGroup("proc")
+			},
+			[]slog.Attr{slog.Int("pid", 7)},
+			"[20:05:09] DEBUG: event source=gamemode proc.pid=7\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			var h slog.Handler = NewConsoleHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+			if tc.with != nil {
+				h = tc.with(h)
+			}
+
+			r := slog.NewRecord(at, tc.level, tc.msg, 0)
+			r.AddAttrs(tc.attrs...)
+			if err := h.Handle(context.Background(), r); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tc.want {
+				t.Errorf("got %q, want %q", buf.String(), tc.want)
+			}
+		})
+	}
+}
+
+func TestConsoleHandlerLevel(t *testing.T) {
+	h := NewConsoleHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelWarn})
+	if h.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("info enabled below a warn level")
+	}
+	if !h.Enabled(context.Background(), slog.LevelError) {
+		t.Error("error disabled above a warn level")
+	}
+}
+
+func TestFanout(t *testing.T) {
+	var console, file bytes.Buffer
+	logger := slog.New(fanout{
+		NewConsoleHandler(&console, &slog.HandlerOptions{Level: slog.LevelInfo}),
+		slog.NewTextHandler(&file, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	}).With("game", "Factorio")
+
+	logger.Debug("only in the file")
+	logger.Info("in both")
+
+	if got := console.String(); strings.Contains(got, "only in the file") || !strings.HasSuffix(got, "] in both game=Factorio\n") {
+		t.Errorf("console = %q", got)
+	}
+	if got := file.String(); !strings.Contains(got, `level=DEBUG msg="only in the file" game=Factorio`) ||
+		!strings.Contains(got, `level=INFO msg="in both" game=Factorio`) {
+		t.Errorf("file = %q", got)
+	}
+}
@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Log formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Options configures the monitor's logger
+type Options struct {
+	// Level is debug, info, warn or error
+	Level string
+	// Format is text or json
+	Format string
+	// Console receives log output; File, if set, receives a copy
+	Console io.Writer
+	File    string
+}
+
+// Setup builds a logger from opts and installs it as slog's default. Text
+// logs on a terminal get a [HH:MM:SS] prefix; the file and other consoles
+// get key=value lines. The returned closer releases the log file, if one
+// was opened.
+func Setup(opts Options) (io.Closer, error) {
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: level}
+	var newHandler func(io.Writer) slog.Handler
+	switch opts.Format {
+	case FormatText, "":
+		newHandler = func(w io.Writer) slog.Handler { return slog.NewTextHandler(w, handlerOpts) }
+	case FormatJSON:
+		newHandler = func(w io.Writer) slog.Handler { return slog.NewJSONHandler(w, handlerOpts) }
+	default:
+		return nil, fmt.Errorf("invalid log format %q (expected text or json)", opts.Format)
+	}
+
+	handler := newHandler(opts.Console)
+	if opts.Format != FormatJSON && isTerminal(opts.Console) {
+		handler = NewConsoleHandler(opts.Console, handlerOpts)
+	}
+
+	var closer io.Closer = nopCloser{}
+	if opts.File != "" {
+		f, err := OpenRotating(opts.File, DefaultMaxSize, DefaultBackups)
+		if err != nil {
+			return nil, err
+		}
+		closer = f
+		handler = fanout{handler, newHandler(f)}
+	}
+
+	slog.SetDefault(slog.New(handler))
+	return closer, nil
+}
+
+// ParseLevel converts a level name to a slog level
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("invalid log level %q (expected debug, info, warn or error)", s)
+	}
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
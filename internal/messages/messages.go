@@ -0,0 +1,210 @@
+package messages
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/models"
+	"github.com/austincgause/gametrak/internal/utility"
+)
+
+// Message types that can be templated
+const (
+	Start     = "start"
+	End       = "end"
+	TooShort  = "too_short"
+	Reminder  = "reminder"
+	Milestone = "milestone"
+	Error     = "error"
+)
+
+// Data is what templates can refer to. Session fields are empty for
+// messages that aren't about a session.
+type Data struct {
+	Game    string
+	Class   string
+	Title   string
+	Address string
+	Start   time.Time
+	End     time.Time
+
+	// Duration is the length of this session
+	Duration time.Duration
+
+	// TodayTotal and AllTimeTotal include this session
+	TodayTotal   time.Duration
+	AllTimeTotal time.Duration
+
+	// LongestSession is the game's longest logged session before this one
+	LongestSession time.Duration
+
+	MinSessionMins int
+
+	// Subject and Message carry the text of reminders and errors
+	Subject string
+	Message string
+}
+
+// Rendered is a message with its templates applied
+type Rendered struct {
+	Title   string
+	Body    string
+	Console string
+}
+
+var defaults = map[string]models.MessageTemplate{
+	Start: {
+		Title:   "Game Started",
+		Body:    "{{.Game}}",
+		Console: "Game started: {{.Game}} (class: {{.Class}}, address: {{.Address}})",
+	},
+	End: {
+		Title:   "Game Session Ended",
+		Body:    "{{or .Title .Game}} - {{rounded .Duration}}\nToday: {{rounded .TodayTotal}} · All time: {{rounded .AllTimeTotal}}",
+		Console: "Game ended: {{or .Title .Game}} - Session: {{exact .Duration}}",
+	},
+	TooShort: {
+		Console: "Session too short to log (min: {{.MinSessionMins}} mins)",
+	},
+	Reminder: {
+		Title:   "{{.Subject}}",
+		Body:    "{{.Message}}",
+		Console: "{{.Subject}}: {{.Message}}",
+	},
+	Milestone: {
+		Title:   "{{.Subject}}",
+		Body:    "{{.Message}}",
+		Console: "{{.Subject}}: {{.Message}}",
+	},
+	Error: {
+		Title:   "Gametrak Error",
+		Body:    "{{.Message}}",
+		Console: "{{.Message}}",
+	},
+}
+
+var funcs = template.FuncMap{
+	"rounded": utility.FormatDurationRounded,
+	"exact":   utility.FormatDurationExact,
+	"hours":   func(d time.Duration) string { return fmt.Sprintf("%.1f", d.Hours()) },
+}
+
+// compiled holds the title, body and console templates for one message type
+type compiled struct {
+	title, body, console *template.Template
+}
+
+var (
+	builtin = mustCompileDefaults()
+
+	mu        sync.RWMutex
+	templates = builtin
+)
+
+// Configure replaces the built-in templates with those set in the config.
+// Templates that fail to parse keep their default and are reported.
+func Configure(cfg models.Messages) error {
+	configured := map[string]models.MessageTemplate{
+		Start:     cfg.Start,
+		End:       cfg.End,
+		TooShort:  cfg.TooShort,
+		Reminder:  cfg.Reminder,
+		Milestone: cfg.Milestone,
+		Error:     cfg.Error,
+	}
+
+	var errs []error
+	result := make(map[string]compiled, len(builtin))
+	for kind, c := range builtin {
+		result[kind] = c
+	}
+
+	for kind, tmpl := range configured {
+		c := result[kind]
+		for _, field := range []struct {
+			name string
+			text string
+			dst  **template.Template
+		}{
+			{"title", tmpl.Title, &c.title},
+			{"body", tmpl.Body, &c.body},
+			{"console", tmpl.Console, &c.console},
+		} {
+			if field.text == "" {
+				continue
+			}
+			t, err := parse(kind+"."+field.name, field.text)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			*field.dst = t
+		}
+		result[kind] = c
+	}
+
+	mu.Lock()
+	templates = result
+	mu.Unlock()
+
+	return errors.Join(errs...)
+}
+
+// Render applies the templates for a message type. A template that fails
+// to execute falls back to its default.
+func Render(kind string, data Data) Rendered {
+	mu.RLock()
+	c := templates[kind]
+	mu.RUnlock()
+
+	def := builtin[kind]
+	return Rendered{
+		Title:   execute(c.title, def.title, data),
+		Body:    execute(c.body, def.body, data),
+		Console: execute(c.console, def.console, data),
+	}
+}
+
+func execute(t, fallback *template.Template, data Data) string {
+	if t == nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		if fallback == nil || fallback == t {
+			return ""
+		}
+		return execute(fallback, nil, data)
+	}
+	return buf.String()
+}
+
+func parse(name, text string) (*template.Template, error) {
+	t, err := template.New(name).Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	return t, nil
+}
+
+func mustCompileDefaults() map[string]compiled {
+	result := make(map[string]compiled, len(defaults))
+	for kind, tmpl := range defaults {
+		var c compiled
+		if tmpl.Title != "" {
+			c.title = template.Must(parse(kind+".title", tmpl.Title))
+		}
+		if tmpl.Body != "" {
+			c.body = template.Must(parse(kind+".body", tmpl.Body))
+		}
+		if tmpl.Console != "" {
+			c.console = template.Must(parse(kind+".console", tmpl.Console))
+		}
+		result[kind] = c
+	}
+	return result
+}
@@ -0,0 +1,183 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/austincgause/gametrak/internal/models"
+)
+
+// Metrics holds the monitor's counters and gauges and renders them in the
+// Prometheus text exposition format. A nil *Metrics is valid and records
+// nothing, so callers don't need to check whether metrics are enabled.
+type Metrics struct {
+	mu               sync.Mutex
+	gameSeconds      map[string]int64
+	gameSessions     map[string]int
+	activeSessions   map[string]int
+	eventsByType     map[string]int
+	reconnects       int
+	logWriteFailures int
+}
+
+// New creates a Metrics seeded with the totals from the session history
+func New(history []models.SessionLog) *Metrics {
+	m := &Metrics{
+		gameSeconds:    make(map[string]int64),
+		gameSessions:   make(map[string]int),
+		activeSessions: make(map[string]int),
+		eventsByType:   make(map[string]int),
+	}
+	for _, s := range history {
+		m.gameSeconds[s.Game] += s.DurationSeconds
+		m.gameSessions[s.Game]++
+	}
+	return m
+}
+
+// Event counts a processed Hyprland event by type
+func (m *Metrics) Event(eventType string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.eventsByType[eventType]++
+}
+
+// SessionStarted marks a game as active
+func (m *Metrics) SessionStarted(game string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.activeSessions[game]++
+}
+
+// SessionEnded marks a game as no longer active
+func (m *Metrics) SessionEnded(game string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.activeSessions[game] > 0 {
+		m.activeSessions[game]--
+	}
+}
+
+// SessionLogged adds a session written to the log to the per-game totals
+func (m *Metrics) SessionLogged(game string, seconds int64) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gameSeconds[game] += seconds
+	m.gameSessions[game]++
+}
+
+// Reconnect counts a re-established Hyprland socket connection
+func (m *Metrics) Reconnect() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reconnects++
+}
+
+// LogWriteFailure counts a session that could not be written to the log
+func (m *Metrics) LogWriteFailure() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logWriteFailures++
+}
+
+// WriteTo renders all metrics in the Prometheus text format
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	writeHeader(&b, "gametrak_game_seconds_total", "counter", "Total logged playtime per game in seconds.")
+	for _, game := range sortedKeys(m.gameSeconds) {
+		fmt.Fprintf(&b, "gametrak_game_seconds_total{game=\"%s\"} %d\n", escapeLabel(game), m.gameSeconds[game])
+	}
+
+	writeHeader(&b, "gametrak_game_sessions_total", "counter", "Number of logged sessions per game.")
+	for _, game := range sortedKeys(m.gameSessions) {
+		fmt.Fprintf(&b, "gametrak_game_sessions_total{game=\"%s\"} %d\n", escapeLabel(game), m.gameSessions[game])
+	}
+
+	writeHeader(&b, "gametrak_active_sessions", "gauge", "Number of currently running sessions per game.")
+	for _, game := range sortedKeys(m.activeSessions) {
+		fmt.Fprintf(&b, "gametrak_active_sessions{game=\"%s\"} %d\n", escapeLabel(game), m.activeSessions[game])
+	}
+
+	writeHeader(&b, "gametrak_events_total", "counter", "Hyprland events processed by type.")
+	for _, eventType := range sortedKeys(m.eventsByType) {
+		fmt.Fprintf(&b, "gametrak_events_total{type=\"%s\"} %d\n", escapeLabel(eventType), m.eventsByType[eventType])
+	}
+
+	writeHeader(&b, "gametrak_socket_reconnects_total", "counter", "Reconnections to the Hyprland event socket.")
+	fmt.Fprintf(&b, "gametrak_socket_reconnects_total %d\n", m.reconnects)
+
+	writeHeader(&b, "gametrak_log_write_failures_total", "counter", "Sessions that failed to be written to the log.")
+	fmt.Fprintf(&b, "gametrak_log_write_failures_total %d\n", m.logWriteFailures)
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ServeHTTP exposes the metrics on any path it is mounted at
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// Serve starts an HTTP listener exposing /metrics on addr. Binding errors are
+// returned immediately; the server then runs in the background.
+func (m *Metrics) Serve(addr string) (*http.Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m)
+
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+
+	return srv, nil
+}
+
+func writeHeader(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func escapeLabel(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -4,10 +4,12 @@ import "time"
 
 // Game represents a game to track
 type Game struct {
-	Class    string `mapstructure:"class" yaml:"class"`
-	Name     string `mapstructure:"name,omitempty" yaml:"name,omitempty"`
-	Prefix   bool   `mapstructure:"prefix,omitempty" yaml:"prefix,omitempty"`
-	UseTitle bool   `mapstructure:"use_title,omitempty" yaml:"use_title,omitempty"`
+	Class    string   `mapstructure:"class" yaml:"class"`
+	Name     string   `mapstructure:"name,omitempty" yaml:"name,omitempty"`
+	Prefix   bool     `mapstructure:"prefix,omitempty" yaml:"prefix,omitempty"`
+	UseTitle bool     `mapstructure:"use_title,omitempty" yaml:"use_title,omitempty"`
+	Limits   Limits   `mapstructure:"limits,omitempty" yaml:"limits,omitempty"`
+	Schedule Schedule `mapstructure:"schedule,omitempty" yaml:"schedule,omitempty"`
 }
 
 // DisplayName returns the game's display name, falling back to class if not set
@@ -25,15 +27,76 @@ type Session struct {
 	Title     string
 	GameName  string
 	StartTime time.Time
+	Source    string
 }
 
-// SessionLog represents a completed session for persistent storage
+// SessionLog represents a completed session for persistent storage. Entries
+// written before IDs existed are given one derived from their contents
+// when loaded.
 type SessionLog struct {
+	ID              string `json:"id,omitempty"`
 	Game            string `json:"game"`
 	Class           string `json:"class"`
 	Start           string `json:"start"`
 	End             string `json:"end"`
 	DurationSeconds int64  `json:"duration_seconds"`
+	Source          string `json:"source,omitempty"`
+}
+
+// SourceManual marks sessions timed by hand with gametrak start and stop,
+// rather than detected by the monitor
+const SourceManual = "manual"
+
+// SourceRun marks sessions recorded by gametrak run for the game it launched
+const SourceRun = "run"
+
+// Timer is a manual session that has been started but not yet stopped
+type Timer struct {
+	Game  string `json:"game"`
+	Class string `json:"class,omitempty"`
+	Start string `json:"start"`
+}
+
+// JournalEntry records one change made to the session log by hand, so it
+// can be undone. Before holds the affected sessions as they were and After
+// as they became; an added session has no before-image and a deleted one
+// no after-image.
+type JournalEntry struct {
+	Time        string       `json:"time"`
+	Command     string       `json:"command"`
+	Description string       `json:"description"`
+	Before      []SessionLog `json:"before,omitempty"`
+	After       []SessionLog `json:"after,omitempty"`
+}
+
+// ViolationLog records a game played outside its allowed schedule
+type ViolationLog struct {
+	Time   string `json:"time"`
+	Game   string `json:"game"`
+	Class  string `json:"class"`
+	Rule   string `json:"rule"`
+	Action string `json:"action"`
+}
+
+// Baseline is playtime imported from another launcher, counted in all-time
+// totals alongside the session log. Seconds excludes time gametrak had
+// already logged for the game when it was imported.
+type Baseline struct {
+	Game     string `json:"game"`
+	Source   string `json:"source"`
+	SourceID string `json:"source_id,omitempty"`
+	Seconds  int64  `json:"seconds"`
+	Imported string `json:"imported"`
+}
+
+// BankTransaction is one entry in the time bank ledger. Positive minutes are
+// credits; negative minutes are playtime drawn from the bank. Auto marks
+// debits made by the monitor once the daily budget is exceeded.
+type BankTransaction struct {
+	Time    string `json:"time"`
+	Minutes int    `json:"minutes"`
+	Reason  string `json:"reason,omitempty"`
+	Auto    bool   `json:"auto,omitempty"`
 }
 
 // Settings holds application settings
@@ -43,10 +106,163 @@ type Settings struct {
 	SessionsFile   string `mapstructure:"sessions_file" yaml:"sessions_file"`
 	HyprlandConf   string `mapstructure:"hyprland_conf" yaml:"hyprland_conf"`
 	MinSessionMins int    `mapstructure:"min_session_mins" yaml:"min_session_mins,omitempty"`
+	ViolationsFile string `mapstructure:"violations_file" yaml:"violations_file,omitempty"`
+	BankFile       string `mapstructure:"bank_file" yaml:"bank_file,omitempty"`
+	BaselinesFile  string `mapstructure:"baselines_file" yaml:"baselines_file,omitempty"`
+
+	// SteamDir is the Steam installation used to name steam_app_ windows;
+	// empty finds it automatically
+	SteamDir string `mapstructure:"steam_dir" yaml:"steam_dir,omitempty"`
+}
+
+// Limits caps playtime per day and per week. Zero means unlimited.
+type Limits struct {
+	DailyMins  int `mapstructure:"daily_mins" yaml:"daily_mins,omitempty"`
+	WeeklyMins int `mapstructure:"weekly_mins" yaml:"weekly_mins,omitempty"`
+}
+
+// IsSet reports whether any limit is configured
+func (l Limits) IsSet() bool {
+	return l.DailyMins > 0 || l.WeeklyMins > 0
+}
+
+// Budgets holds the global playtime limits and how they are enforced.
+// WarnAt lists the percentages of a limit at which a warning is sent.
+// Enforce closes game windows once a limit is exceeded.
+type Budgets struct {
+	Limits  `mapstructure:",squash" yaml:",inline"`
+	WarnAt  []int `mapstructure:"warn_at" yaml:"warn_at,omitempty"`
+	Enforce bool  `mapstructure:"enforce" yaml:"enforce,omitempty"`
+}
+
+// Schedule modes decide what happens when a game is played outside its schedule
+const (
+	ScheduleWarn  = "warn"
+	ScheduleLog   = "log"
+	ScheduleClose = "close"
+)
+
+// TimeWindow is a recurring time of day on selected days, e.g. weekdays
+// 08:00-16:00. Days accepts names (mon, tuesday), "weekdays", "weekends" or
+// "daily"; an empty list means every day. A window whose To is earlier than
+// its From runs past midnight into the next day.
+type TimeWindow struct {
+	Days []string `mapstructure:"days" yaml:"days,omitempty"`
+	From string   `mapstructure:"from" yaml:"from"`
+	To   string   `mapstructure:"to" yaml:"to"`
+}
+
+// Schedule restricts when games may be played. On days covered by an Allow
+// window, play is only permitted inside one of them. Play inside any Deny
+// window is never permitted. Mode is one of warn, log or close.
+type Schedule struct {
+	Allow []TimeWindow `mapstructure:"allow" yaml:"allow,omitempty"`
+	Deny  []TimeWindow `mapstructure:"deny" yaml:"deny,omitempty"`
+	Mode  string       `mapstructure:"mode" yaml:"mode,omitempty"`
+}
+
+// IsSet reports whether the schedule has any rules
+func (s Schedule) IsSet() bool {
+	return len(s.Allow) > 0 || len(s.Deny) > 0
+}
+
+// Policies for games GameMode reports that aren't in the games list
+const (
+	AutoDiscoverIgnore = "ignore"
+	AutoDiscoverRecord = "record"
+	AutoDiscoverAdd    = "add"
+)
+
+// GameMode configures detecting games through Feral GameMode. AutoDiscover
+// decides what happens to games that aren't configured: ignore them,
+// record them under their executable name, or also remember them for
+// gametrak add --discovered to add to the games list.
+type GameMode struct {
+	Enabled      bool   `mapstructure:"enabled" yaml:"enabled,omitempty"`
+	AutoDiscover string `mapstructure:"auto_discover" yaml:"auto_discover,omitempty"`
+}
+
+// ActivityWatch configures exporting sessions to an aw-server
+type ActivityWatch struct {
+	Enabled bool   `mapstructure:"enabled" yaml:"enabled,omitempty"`
+	URL     string `mapstructure:"url" yaml:"url,omitempty"`
+	Bucket  string `mapstructure:"bucket" yaml:"bucket,omitempty"`
+}
+
+// Metrics configures the monitor's Prometheus endpoint
+type Metrics struct {
+	Enabled bool   `mapstructure:"enabled" yaml:"enabled,omitempty"`
+	Listen  string `mapstructure:"listen" yaml:"listen,omitempty"`
+}
+
+// MQTT configures publishing state and sessions to an MQTT broker, with
+// Home Assistant discovery
+type MQTT struct {
+	Enabled          bool   `mapstructure:"enabled" yaml:"enabled,omitempty"`
+	Broker           string `mapstructure:"broker" yaml:"broker,omitempty"`
+	Username         string `mapstructure:"username" yaml:"username,omitempty"`
+	Password         string `mapstructure:"password" yaml:"password,omitempty"`
+	ClientID         string `mapstructure:"client_id" yaml:"client_id,omitempty"`
+	Topic            string `mapstructure:"topic" yaml:"topic,omitempty"`
+	DiscoveryPrefix  string `mapstructure:"discovery_prefix" yaml:"discovery_prefix,omitempty"`
+	DisableDiscovery bool   `mapstructure:"disable_discovery" yaml:"disable_discovery,omitempty"`
+}
+
+// NotifierBackend configures one notification backend. Type is one of
+// notify-send, dbus, hyprland, ntfy, gotify or stdout. Urgency maps
+// gametrak's urgencies (low, normal, critical) to backend-specific values.
+type NotifierBackend struct {
+	Type    string            `mapstructure:"type" yaml:"type"`
+	URL     string            `mapstructure:"url" yaml:"url,omitempty"`
+	Token   string            `mapstructure:"token" yaml:"token,omitempty"`
+	Urgency map[string]string `mapstructure:"urgency" yaml:"urgency,omitempty"`
+}
+
+// Notifiers configures named notification backends and which of them
+// receive each event type (started, game_started, game_ended, error,
+// reminder, milestone). Events without a route go to every backend.
+type Notifiers struct {
+	Backends map[string]NotifierBackend `mapstructure:"backends" yaml:"backends,omitempty"`
+	Routes   map[string][]string        `mapstructure:"routes" yaml:"routes,omitempty"`
+}
+
+// MessageTemplate holds text/template strings for one message type: the
+// notification title and body, and the line printed by the monitor. Empty
+// fields keep the built-in default.
+type MessageTemplate struct {
+	Title   string `mapstructure:"title" yaml:"title,omitempty"`
+	Body    string `mapstructure:"body" yaml:"body,omitempty"`
+	Console string `mapstructure:"console" yaml:"console,omitempty"`
+}
+
+// Messages holds the templates for each message type
+type Messages struct {
+	Start     MessageTemplate `mapstructure:"start" yaml:"start,omitempty"`
+	End       MessageTemplate `mapstructure:"end" yaml:"end,omitempty"`
+	TooShort  MessageTemplate `mapstructure:"too_short" yaml:"too_short,omitempty"`
+	Reminder  MessageTemplate `mapstructure:"reminder" yaml:"reminder,omitempty"`
+	Milestone MessageTemplate `mapstructure:"milestone" yaml:"milestone,omitempty"`
+	Error     MessageTemplate `mapstructure:"error" yaml:"error,omitempty"`
+}
+
+// Milestones configures notifications for playtime achievements: a game's
+// all-time total passing one of Hours, or a new longest session for a game
+type Milestones struct {
+	Hours          []int `mapstructure:"hours" yaml:"hours,omitempty"`
+	LongestSession bool  `mapstructure:"longest_session" yaml:"longest_session,omitempty"`
 }
 
 // Config represents the full configuration structure
 type Config struct {
-	Games    []Game   `mapstructure:"games"`
-	Settings Settings `mapstructure:"settings"`
+	Games         []Game        `mapstructure:"games"`
+	Settings      Settings      `mapstructure:"settings"`
+	Budgets       Budgets       `mapstructure:"budgets" yaml:"budgets,omitempty"`
+	Schedule      Schedule      `mapstructure:"schedule" yaml:"schedule,omitempty"`
+	Metrics       Metrics       `mapstructure:"metrics" yaml:"metrics,omitempty"`
+	ActivityWatch ActivityWatch `mapstructure:"activitywatch" yaml:"activitywatch,omitempty"`
+	GameMode      GameMode      `mapstructure:"gamemode" yaml:"gamemode,omitempty"`
+	MQTT          MQTT          `mapstructure:"mqtt" yaml:"mqtt,omitempty"`
+	Notifiers     Notifiers     `mapstructure:"notifiers" yaml:"notifiers,omitempty"`
+	Messages      Messages      `mapstructure:"messages" yaml:"messages,omitempty"`
+	Milestones    Milestones    `mapstructure:"milestones" yaml:"milestones,omitempty"`
 }
@@ -0,0 +1,354 @@
+package mqtt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// MQTT 3.1.1 control packet types (upper nibble of the fixed header)
+const (
+	packetConnect    = 0x10
+	packetConnack    = 0x20
+	packetPublish    = 0x30
+	packetPingreq    = 0xC0
+	packetPingresp   = 0xD0
+	packetDisconnect = 0xE0
+)
+
+const (
+	defaultKeepAlive = 30 * time.Second
+	queueSize        = 256
+	maxBackoff       = time.Minute
+)
+
+// Message is a QoS 0 publish
+type Message struct {
+	Topic   string
+	Payload []byte
+	Retain  bool
+}
+
+// Options configures a Client
+type Options struct {
+	Broker    string // host:port
+	ClientID  string
+	Username  string
+	Password  string
+	KeepAlive time.Duration
+
+	// Will is published by the broker if the connection drops without a
+	// clean disconnect
+	Will *Message
+
+	// OnConnect is called after every successful (re)connection, before
+	// queued messages are sent
+	OnConnect func()
+
+	// Logf reports connection problems; nil discards them
+	Logf func(format string, args ...any)
+}
+
+// Client is a minimal MQTT 3.1.1 publisher supporting QoS 0 only. It
+// connects in the background, reconnects with backoff when the connection
+// drops, and buffers messages while offline. When the buffer is full the
+// oldest messages are dropped.
+type Client struct {
+	opts  Options
+	queue chan Message
+	stop  chan struct{}
+	done  chan struct{}
+	once  sync.Once
+}
+
+// NewClient creates a client; call Start to begin connecting
+func NewClient(opts Options) *Client {
+	if opts.KeepAlive <= 0 {
+		opts.KeepAlive = defaultKeepAlive
+	}
+	if opts.Logf == nil {
+		opts.Logf = func(string, ...any) {}
+	}
+	return &Client{
+		opts:  opts,
+		queue: make(chan Message, queueSize),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+}
+
+// Start runs the connection loop in the background
+func (c *Client) Start() {
+	go c.run()
+}
+
+// Publish queues a message for delivery. It never blocks.
+func (c *Client) Publish(topic string, payload []byte, retain bool) {
+	msg := Message{Topic: topic, Payload: payload, Retain: retain}
+	for {
+		select {
+		case c.queue <- msg:
+			return
+		default:
+		}
+		// Buffer full: drop the oldest message to make room
+		select {
+		case <-c.queue:
+		default:
+		}
+	}
+}
+
+// Close sends any queued messages if connected, disconnects cleanly and
+// waits up to timeout for the connection loop to finish
+func (c *Client) Close(timeout time.Duration) {
+	c.once.Do(func() { close(c.stop) })
+	select {
+	case <-c.done:
+	case <-time.After(timeout):
+	}
+}
+
+func (c *Client) run() {
+	defer close(c.done)
+
+	backoff := time.Second
+	var pending *Message
+
+	for {
+		conn, err := c.connect()
+		if err != nil {
+			c.opts.Logf("MQTT connection to %s failed: %v (retrying in %s)", c.opts.Broker, err, backoff)
+			select {
+			case <-c.stop:
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, maxBackoff)
+			continue
+		}
+		backoff = time.Second
+
+		if c.opts.OnConnect != nil {
+			c.opts.OnConnect()
+		}
+
+		var stopped bool
+		pending, stopped = c.serve(conn, pending)
+		conn.Close()
+		if stopped {
+			return
+		}
+	}
+}
+
+// serve publishes queued messages and keeps the connection alive until it
+// fails or the client is stopped. A message whose write failed is returned
+// so it can be retried after reconnecting.
+func (c *Client) serve(conn net.Conn, pending *Message) (*Message, bool) {
+	readErr := make(chan error, 1)
+	go c.readLoop(conn, readErr)
+
+	ping := time.NewTicker(c.opts.KeepAlive / 2)
+	defer ping.Stop()
+
+	if pending != nil {
+		if err := c.write(conn, encodePublish(*pending)); err != nil {
+			c.opts.Logf("MQTT publish failed: %v", err)
+			return pending, false
+		}
+	}
+
+	for {
+		select {
+		case <-c.stop:
+			c.flush(conn)
+			c.write(conn, []byte{packetDisconnect, 0})
+			return nil, true
+
+		case err := <-readErr:
+			c.opts.Logf("MQTT connection lost: %v", err)
+			return nil, false
+
+		case <-ping.C:
+			if err := c.write(conn, []byte{packetPingreq, 0}); err != nil {
+				c.opts.Logf("MQTT ping failed: %v", err)
+				return nil, false
+			}
+
+		case msg := <-c.queue:
+			if err := c.write(conn, encodePublish(msg)); err != nil {
+				c.opts.Logf("MQTT publish failed: %v", err)
+				return &msg, false
+			}
+		}
+	}
+}
+
+// flush writes whatever is still queued without blocking
+func (c *Client) flush(conn net.Conn) {
+	for {
+		select {
+		case msg := <-c.queue:
+			if err := c.write(conn, encodePublish(msg)); err != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (c *Client) write(conn net.Conn, packet []byte) error {
+	conn.SetWriteDeadline(time.Now().Add(c.opts.KeepAlive))
+	_, err := conn.Write(packet)
+	return err
+}
+
+// readLoop drains packets from the broker. The broker answers our pings, so
+// going a keep-alive period and a half without any packet means the
+// connection is dead.
+func (c *Client) readLoop(conn net.Conn, errs chan<- error) {
+	r := bufio.NewReader(conn)
+	for {
+		conn.SetReadDeadline(time.Now().Add(c.opts.KeepAlive * 3 / 2))
+		if _, _, err := readPacket(r); err != nil {
+			errs <- err
+			return
+		}
+	}
+}
+
+func (c *Client) connect() (net.Conn, error) {
+	conn, err := net.DialTimeout("tcp", c.opts.Broker, 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	if _, err := conn.Write(encodeConnect(c.opts)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	header, body, err := readPacket(bufio.NewReader(conn))
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("waiting for CONNACK: %w", err)
+	}
+	if header&0xF0 != packetConnack || len(body) != 2 {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected packet 0x%02x waiting for CONNACK", header)
+	}
+	if body[1] != 0 {
+		conn.Close()
+		return nil, fmt.Errorf("broker refused connection (code %d)", body[1])
+	}
+
+	conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+func encodeConnect(opts Options) []byte {
+	var flags byte = 0x02 // clean session
+	var payload []byte
+	payload = appendString(payload, opts.ClientID)
+
+	if opts.Will != nil {
+		flags |= 0x04
+		if opts.Will.Retain {
+			flags |= 0x20
+		}
+		payload = appendString(payload, opts.Will.Topic)
+		payload = appendBytes(payload, opts.Will.Payload)
+	}
+	if opts.Username != "" {
+		flags |= 0x80
+		payload = appendString(payload, opts.Username)
+		if opts.Password != "" {
+			flags |= 0x40
+			payload = appendString(payload, opts.Password)
+		}
+	}
+
+	var body []byte
+	body = appendString(body, "MQTT")
+	body = append(body, 4, flags) // protocol level 4 = 3.1.1
+	body = binary.BigEndian.AppendUint16(body, uint16(opts.KeepAlive/time.Second))
+	body = append(body, payload...)
+
+	return packet(packetConnect, body)
+}
+
+func encodePublish(msg Message) []byte {
+	header := byte(packetPublish)
+	if msg.Retain {
+		header |= 0x01
+	}
+	body := appendString(nil, msg.Topic)
+	body = append(body, msg.Payload...)
+	return packet(header, body)
+}
+
+func packet(header byte, body []byte) []byte {
+	out := []byte{header}
+	out = appendRemainingLength(out, len(body))
+	return append(out, body...)
+}
+
+func appendString(b []byte, s string) []byte {
+	return appendBytes(b, []byte(s))
+}
+
+func appendBytes(b, data []byte) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(data)))
+	return append(b, data...)
+}
+
+func appendRemainingLength(b []byte, n int) []byte {
+	for {
+		digit := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			digit |= 0x80
+		}
+		b = append(b, digit)
+		if n == 0 {
+			return b
+		}
+	}
+}
+
+func readPacket(r *bufio.Reader) (header byte, body []byte, err error) {
+	header, err = r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		if i == 4 {
+			return 0, nil, errors.New("malformed remaining length")
+		}
+		digit, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(digit&0x7F) * multiplier
+		multiplier *= 128
+		if digit&0x80 == 0 {
+			break
+		}
+	}
+
+	body = make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
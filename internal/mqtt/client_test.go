@@ -0,0 +1,267 @@
+package mqtt
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBroker accepts MQTT connections on a local listener, acknowledges
+// them and records what clients send
+type fakeBroker struct {
+	t        *testing.T
+	listener net.Listener
+	connects chan []byte  // CONNECT bodies
+	messages chan Message // PUBLISH packets
+
+	mu    sync.Mutex
+	conns []net.Conn
+}
+
+func newFakeBroker(t *testing.T) *fakeBroker {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &fakeBroker{
+		t:        t,
+		listener: l,
+		connects: make(chan []byte, 16),
+		messages: make(chan Message, 256),
+	}
+	go b.serve(l)
+	t.Cleanup(b.close)
+	return b
+}
+
+func (b *fakeBroker) addr() string {
+	return b.listener.Addr().String()
+}
+
+func (b *fakeBroker) serve(l net.Listener) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		b.mu.Lock()
+		b.conns = append(b.conns, conn)
+		b.mu.Unlock()
+		go b.handle(conn)
+	}
+}
+
+func (b *fakeBroker) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		header, body, err := readPacket(r)
+		if err != nil {
+			return
+		}
+		switch header & 0xF0 {
+		case packetConnect:
+			b.connects <- body
+			conn.Write([]byte{packetConnack, 2, 0, 0})
+		case packetPublish:
+			n := int(binary.BigEndian.Uint16(body))
+			b.messages <- Message{
+				Topic:   string(body[2 : 2+n]),
+				Payload: body[2+n:],
+				Retain:  header&0x01 != 0,
+			}
+		case packetPingreq:
+			conn.Write([]byte{packetPingresp, 0})
+		case packetDisconnect:
+			return
+		}
+	}
+}
+
+// drop closes every open client connection without a DISCONNECT
+func (b *fakeBroker) drop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, c := range b.conns {
+		c.Close()
+	}
+	b.conns = nil
+}
+
+func (b *fakeBroker) close() {
+	b.listener.Close()
+	b.drop()
+}
+
+func (b *fakeBroker) waitConnect() []byte {
+	b.t.Helper()
+	select {
+	case body := <-b.connects:
+		return body
+	case <-time.After(5 * time.Second):
+		b.t.Fatal("timed out waiting for CONNECT")
+		return nil
+	}
+}
+
+func (b *fakeBroker) waitMessage() Message {
+	b.t.Helper()
+	select {
+	case msg := <-b.messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		b.t.Fatal("timed out waiting for PUBLISH")
+		return Message{}
+	}
+}
+
+// readString reads a length-prefixed string from the front of b
+func readString(t *testing.T, b []byte) (string, []byte) {
+	t.Helper()
+	if len(b) < 2 {
+		t.Fatalf("truncated string in %q", b)
+	}
+	n := int(binary.BigEndian.Uint16(b))
+	return string(b[2 : 2+n]), b[2+n:]
+}
+
+func TestConnect(t *testing.T) {
+	broker := newFakeBroker(t)
+	c := NewClient(Options{
+		Broker:    broker.addr(),
+		ClientID:  "gametrak-test",
+		Username:  "user",
+		Password:  "secret",
+		KeepAlive: 20 * time.Second,
+		Will:      &Message{Topic: "gametrak/status", Payload: []byte("offline"), Retain: true},
+	})
+	c.Start()
+	defer c.Close(time.Second)
+
+	body := broker.waitConnect()
+	protocol, rest := readString(t, body)
+	if protocol != "MQTT" || rest[0] != 4 {
+		t.Fatalf("protocol = %q level %d, want MQTT level 4", protocol, rest[0])
+	}
+	if flags := rest[1]; flags != 0x02|0x04|0x20|0x40|0x80 {
+		t.Errorf("connect flags = %08b, want clean session, retained will, username and password", flags)
+	}
+	if keepAlive := binary.BigEndian.Uint16(rest[2:]); keepAlive != 20 {
+		t.Errorf("keep alive = %d, want 20", keepAlive)
+	}
+
+	var got []string
+	for payload := rest[4:]; len(payload) > 0; {
+		var s string
+		s, payload = readString(t, payload)
+		got = append(got, s)
+	}
+	want := []string{"gametrak-test", "gametrak/status", "offline", "user", "secret"}
+	if len(got) != len(want) {
+		t.Fatalf("connect payload = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("connect payload[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPublish(t *testing.T) {
+	broker := newFakeBroker(t)
+	c := NewClient(Options{Broker: broker.addr(), ClientID: "test"})
+	c.Start()
+	defer c.Close(time.Second)
+	broker.waitConnect()
+
+	c.Publish("gametrak/state", []byte(`{"playing":true}`), true)
+	c.Publish("gametrak/session/start", []byte(`{}`), false)
+
+	if msg := broker.waitMessage(); msg.Topic != "gametrak/state" || !msg.Retain || string(msg.Payload) != `{"playing":true}` {
+		t.Errorf("first message = %s %q retain=%v, want retained state", msg.Topic, msg.Payload, msg.Retain)
+	}
+	if msg := broker.waitMessage(); msg.Topic != "gametrak/session/start" || msg.Retain {
+		t.Errorf("second message = %s retain=%v, want unretained session/start", msg.Topic, msg.Retain)
+	}
+}
+
+func TestBufferWhileOffline(t *testing.T) {
+	// Reserve an address, then free it so the first connection attempts fail
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	failed := make(chan struct{}, 1)
+	c := NewClient(Options{
+		Broker:   addr,
+		ClientID: "test",
+		Logf: func(string, ...any) {
+			select {
+			case failed <- struct{}{}:
+			default:
+			}
+		},
+	})
+	c.Start()
+	defer c.Close(time.Second)
+
+	select {
+	case <-failed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("connection attempt didn't fail")
+	}
+	for i := 0; i < 3; i++ {
+		c.Publish("gametrak/queued", []byte{byte('0' + i)}, false)
+	}
+
+	l, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("can't listen on %s again: %v", addr, err)
+	}
+	broker := &fakeBroker{t: t, listener: l, connects: make(chan []byte, 16), messages: make(chan Message, 256)}
+	go broker.serve(l)
+	t.Cleanup(broker.close)
+
+	broker.waitConnect()
+	for i := 0; i < 3; i++ {
+		if msg := broker.waitMessage(); !bytes.Equal(msg.Payload, []byte{byte('0' + i)}) {
+			t.Fatalf("message %d = %q, want queued messages in order", i, msg.Payload)
+		}
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	broker := newFakeBroker(t)
+	connected := make(chan struct{}, 4)
+	c := NewClient(Options{
+		Broker:    broker.addr(),
+		ClientID:  "test",
+		OnConnect: func() { connected <- struct{}{} },
+	})
+	c.Start()
+	defer c.Close(time.Second)
+
+	broker.waitConnect()
+	<-connected
+	broker.drop()
+
+	broker.waitConnect()
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnConnect not called after reconnecting")
+	}
+
+	c.Publish("gametrak/after", []byte("x"), false)
+	if msg := broker.waitMessage(); msg.Topic != "gametrak/after" {
+		t.Errorf("message after reconnect = %s, want gametrak/after", msg.Topic)
+	}
+}
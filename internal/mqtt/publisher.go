@@ -0,0 +1,175 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// State is the retained snapshot published to <base>/state. The today
+// totals count from DayStart, which Home Assistant uses as their last reset.
+type State struct {
+	Playing      bool             `json:"playing"`
+	Game         string           `json:"game"`
+	Since        string           `json:"since,omitempty"`
+	TodaySeconds int64            `json:"today_seconds"`
+	TodayByGame  map[string]int64 `json:"today_by_game"`
+	DayStart     string           `json:"day_start"`
+	Updated      string           `json:"updated"`
+}
+
+// SessionEvent is published to <base>/session/start and <base>/session/end
+type SessionEvent struct {
+	Game            string `json:"game"`
+	Class           string `json:"class"`
+	Title           string `json:"title,omitempty"`
+	Start           string `json:"start"`
+	End             string `json:"end,omitempty"`
+	DurationSeconds int64  `json:"duration_seconds,omitempty"`
+}
+
+// Publisher sends gametrak state and session events over MQTT, along with
+// Home Assistant discovery configs. A nil *Publisher publishes nothing.
+type Publisher struct {
+	client          *Client
+	base            string
+	discoveryPrefix string
+
+	// mu guards last, the latest state, which is re-sent after reconnecting
+	// in case the broker lost it
+	mu   sync.Mutex
+	last []byte
+}
+
+// PublisherOptions configures a Publisher
+type PublisherOptions struct {
+	Options
+	BaseTopic       string // e.g. "gametrak"
+	DiscoveryPrefix string // e.g. "homeassistant"; empty disables discovery
+}
+
+// NewPublisher connects to the broker in the background. The availability
+// topic is set to "offline" by the broker if gametrak disappears.
+func NewPublisher(opts PublisherOptions) *Publisher {
+	p := &Publisher{base: opts.BaseTopic, discoveryPrefix: opts.DiscoveryPrefix}
+
+	opts.Will = &Message{Topic: p.topic("status"), Payload: []byte("offline"), Retain: true}
+	opts.OnConnect = p.onConnect
+	p.client = NewClient(opts.Options)
+	p.client.Start()
+
+	return p
+}
+
+func (p *Publisher) topic(name string) string {
+	return p.base + "/" + name
+}
+
+func (p *Publisher) onConnect() {
+	p.client.Publish(p.topic("status"), []byte("online"), true)
+	if p.discoveryPrefix != "" {
+		p.publishDiscovery()
+	}
+	p.mu.Lock()
+	last := p.last
+	p.mu.Unlock()
+	if last != nil {
+		p.client.Publish(p.topic("state"), last, true)
+	}
+}
+
+// PublishState updates the retained state topic
+func (p *Publisher) PublishState(s State) {
+	if p == nil {
+		return
+	}
+	s.Updated = time.Now().Format(time.RFC3339)
+	data, err := json.Marshal(s)
+	if err != nil {
+		return
+	}
+	p.mu.Lock()
+	p.last = data
+	p.mu.Unlock()
+	p.client.Publish(p.topic("state"), data, true)
+}
+
+// SessionStarted publishes a session start event
+func (p *Publisher) SessionStarted(ev SessionEvent) {
+	p.publishEvent("session/start", ev)
+}
+
+// SessionEnded publishes a session end event
+func (p *Publisher) SessionEnded(ev SessionEvent) {
+	p.publishEvent("session/end", ev)
+}
+
+func (p *Publisher) publishEvent(name string, ev SessionEvent) {
+	if p == nil {
+		return
+	}
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	p.client.Publish(p.topic(name), data, false)
+}
+
+// Close marks gametrak offline and disconnects
+func (p *Publisher) Close() {
+	if p == nil {
+		return
+	}
+	p.client.Publish(p.topic("status"), []byte("offline"), true)
+	p.client.Close(2 * time.Second)
+}
+
+// publishDiscovery announces the Home Assistant entities backed by the
+// state topic
+func (p *Publisher) publishDiscovery() {
+	device := map[string]any{
+		"identifiers": []string{"gametrak"},
+		"name":        "Gametrak",
+	}
+
+	entities := []struct {
+		component string
+		id        string
+		config    map[string]any
+	}{
+		{"binary_sensor", "playing", map[string]any{
+			"name":           "Playing",
+			"value_template": "{{ 'ON' if value_json.playing else 'OFF' }}",
+			"icon":           "mdi:gamepad-variant",
+		}},
+		{"sensor", "current_game", map[string]any{
+			"name":           "Current game",
+			"value_template": "{{ value_json.game if value_json.playing else 'None' }}",
+			"icon":           "mdi:controller",
+		}},
+		{"sensor", "today", map[string]any{
+			"name":                      "Playtime today",
+			"value_template":            "{{ (value_json.today_seconds / 60) | round(0) }}",
+			"unit_of_measurement":       "min",
+			"device_class":              "duration",
+			"state_class":               "total",
+			"last_reset_value_template": "{{ value_json.day_start }}",
+			"json_attributes_topic":     p.topic("state"),
+			"json_attributes_template":  "{{ value_json.today_by_game | tojson }}",
+		}},
+	}
+
+	for _, e := range entities {
+		e.config["unique_id"] = "gametrak_" + e.id
+		e.config["state_topic"] = p.topic("state")
+		e.config["availability_topic"] = p.topic("status")
+		e.config["device"] = device
+
+		data, err := json.Marshal(e.config)
+		if err != nil {
+			continue
+		}
+		topic := p.discoveryPrefix + "/" + e.component + "/gametrak/" + e.id + "/config"
+		p.client.Publish(topic, data, true)
+	}
+}
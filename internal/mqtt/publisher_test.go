@@ -0,0 +1,181 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// collect reads messages from the broker until every topic in want has
+// arrived, returning the last message seen on each topic
+func collect(t *testing.T, broker *fakeBroker, want ...string) map[string]Message {
+	t.Helper()
+	got := make(map[string]Message)
+	missing := func() bool {
+		for _, topic := range want {
+			if _, ok := got[topic]; !ok {
+				return true
+			}
+		}
+		return false
+	}
+	for missing() {
+		msg := broker.waitMessage()
+		got[msg.Topic] = msg
+	}
+	return got
+}
+
+func newTestPublisher(t *testing.T, broker *fakeBroker) *Publisher {
+	t.Helper()
+	p := NewPublisher(PublisherOptions{
+		Options:         Options{Broker: broker.addr(), ClientID: "test"},
+		BaseTopic:       "gametrak",
+		DiscoveryPrefix: "homeassistant",
+	})
+	t.Cleanup(p.Close)
+	return p
+}
+
+func TestPublisherState(t *testing.T) {
+	broker := newFakeBroker(t)
+	p := newTestPublisher(t, broker)
+
+	body := broker.waitConnect()
+	_, rest := readString(t, body)
+	_, payload := readString(t, rest[4:]) // client ID
+	willTopic, payload := readString(t, payload)
+	willPayload, _ := readString(t, payload)
+	if willTopic != "gametrak/status" || willPayload != "offline" {
+		t.Errorf("will = %s %q, want gametrak/status offline", willTopic, willPayload)
+	}
+
+	p.PublishState(State{Playing: true, Game: "Factorio", TodaySeconds: 600, TodayByGame: map[string]int64{"Factorio": 600}})
+	got := collect(t, broker, "gametrak/status", "gametrak/state")
+
+	if status := got["gametrak/status"]; string(status.Payload) != "online" || !status.Retain {
+		t.Errorf("status = %q retain=%v, want retained online", status.Payload, status.Retain)
+	}
+
+	msg := got["gametrak/state"]
+	if !msg.Retain {
+		t.Error("state was not retained")
+	}
+	var state State
+	if err := json.Unmarshal(msg.Payload, &state); err != nil {
+		t.Fatalf("invalid state %q: %v", msg.Payload, err)
+	}
+	if !state.Playing || state.Game != "Factorio" || state.TodaySeconds != 600 || state.TodayByGame["Factorio"] != 600 {
+		t.Errorf("state = %+v", state)
+	}
+	if _, err := time.Parse(time.RFC3339, state.Updated); err != nil {
+		t.Errorf("state updated = %q, want an RFC 3339 time", state.Updated)
+	}
+}
+
+func TestPublisherDiscovery(t *testing.T) {
+	broker := newFakeBroker(t)
+	newTestPublisher(t, broker)
+
+	topics := []string{
+		"homeassistant/binary_sensor/gametrak/playing/config",
+		"homeassistant/sensor/gametrak/current_game/config",
+		"homeassistant/sensor/gametrak/today/config",
+	}
+	got := collect(t, broker, topics...)
+
+	for _, topic := range topics {
+		msg := got[topic]
+		if !msg.Retain {
+			t.Errorf("%s was not retained", topic)
+		}
+
+		var config map[string]any
+		if err := json.Unmarshal(msg.Payload, &config); err != nil {
+			t.Fatalf("%s: invalid config %q: %v", topic, msg.Payload, err)
+		}
+		if config["state_topic"] != "gametrak/state" || config["availability_topic"] != "gametrak/status" {
+			t.Errorf("%s: topics = %v, %v", topic, config["state_topic"], config["availability_topic"])
+		}
+		if id, _ := config["unique_id"].(string); id == "" || config["value_template"] == nil {
+			t.Errorf("%s: missing unique_id or value_template: %v", topic, config)
+		}
+		if device, _ := config["device"].(map[string]any); device["name"] != "Gametrak" {
+			t.Errorf("%s: device = %v", topic, config["device"])
+		}
+	}
+
+	var today map[string]any
+	json.Unmarshal(got[topics[2]].Payload, &today)
+	if today["unit_of_measurement"] != "min" || today["json_attributes_topic"] != "gametrak/state" {
+		t.Errorf("today sensor = %v", today)
+	}
+	// The daily total resets at midnight, so it can't be total_increasing
+	if today["state_class"] != "total" || today["last_reset_value_template"] != "{{ value_json.day_start }}" {
+		t.Errorf("today sensor state_class = %v, last_reset_value_template = %v",
+			today["state_class"], today["last_reset_value_template"])
+	}
+}
+
+func TestPublisherSessionEvents(t *testing.T) {
+	broker := newFakeBroker(t)
+	p := newTestPublisher(t, broker)
+	broker.waitConnect()
+
+	p.SessionStarted(SessionEvent{Game: "Factorio", Class: "factorio", Start: "2025-01-01T20:00:00Z"})
+	p.SessionEnded(SessionEvent{Game: "Factorio", Class: "factorio", Start: "2025-01-01T20:00:00Z", End: "2025-01-01T21:00:00Z", DurationSeconds: 3600})
+	got := collect(t, broker, "gametrak/session/start", "gametrak/session/end")
+
+	for _, topic := range []string{"gametrak/session/start", "gametrak/session/end"} {
+		if got[topic].Retain {
+			t.Errorf("%s was retained", topic)
+		}
+	}
+	var ev SessionEvent
+	if err := json.Unmarshal(got["gametrak/session/end"].Payload, &ev); err != nil || ev.DurationSeconds != 3600 || ev.End == "" {
+		t.Errorf("session end = %q", got["gametrak/session/end"].Payload)
+	}
+}
+
+func TestPublisherRepublishesAfterReconnect(t *testing.T) {
+	broker := newFakeBroker(t)
+	p := newTestPublisher(t, broker)
+
+	broker.waitConnect()
+	p.PublishState(State{Playing: true, Game: "Factorio"})
+	collect(t, broker, "gametrak/status", "gametrak/state")
+
+	// A broker that lost its retained messages gets them again
+	broker.drop()
+	broker.waitConnect()
+	got := collect(t, broker, "gametrak/status", "gametrak/state", "homeassistant/sensor/gametrak/today/config")
+
+	var state State
+	if err := json.Unmarshal(got["gametrak/state"].Payload, &state); err != nil || state.Game != "Factorio" {
+		t.Errorf("state after reconnect = %q", got["gametrak/state"].Payload)
+	}
+}
+
+func TestPublisherClose(t *testing.T) {
+	broker := newFakeBroker(t)
+	p := NewPublisher(PublisherOptions{
+		Options:   Options{Broker: broker.addr(), ClientID: "test"},
+		BaseTopic: "gametrak",
+	})
+	broker.waitConnect()
+	collect(t, broker, "gametrak/status")
+
+	p.Close()
+	msg := broker.waitMessage()
+	if msg.Topic != "gametrak/status" || string(msg.Payload) != "offline" || !msg.Retain {
+		t.Errorf("message on close = %s %q retain=%v, want retained offline status", msg.Topic, msg.Payload, msg.Retain)
+	}
+}
+
+func TestNilPublisher(t *testing.T) {
+	var p *Publisher
+	p.PublishState(State{Playing: true})
+	p.SessionStarted(SessionEvent{Game: "Factorio"})
+	p.SessionEnded(SessionEvent{Game: "Factorio"})
+	p.Close()
+}
@@ -0,0 +1,188 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/hyprland"
+	"github.com/austincgause/gametrak/internal/models"
+	"github.com/austincgause/gametrak/internal/utility"
+	"github.com/godbus/dbus/v5"
+)
+
+// httpTimeout bounds push requests so a slow server can't stall the monitor
+const httpTimeout = 10 * time.Second
+
+func newBackend(name string, b models.NotifierBackend) (Notifier, error) {
+	switch b.Type {
+	case "notify-send":
+		return notifySend{}, nil
+	case "dbus":
+		return dbusNotifier{}, nil
+	case "hyprland":
+		return hyprlandNotifier{}, nil
+	case "ntfy":
+		if b.URL == "" {
+			return nil, fmt.Errorf("ntfy requires a url")
+		}
+		return newQueue(name, ntfyNotifier{url: b.URL, token: b.Token}), nil
+	case "gotify":
+		if b.URL == "" || b.Token == "" {
+			return nil, fmt.Errorf("gotify requires a url and token")
+		}
+		return newQueue(name, gotifyNotifier{url: strings.TrimRight(b.URL, "/"), token: b.Token}), nil
+	case "stdout":
+		return stdoutNotifier{}, nil
+	default:
+		return nil, fmt.Errorf("unknown type %q", b.Type)
+	}
+}
+
+// levelValue translates one of gametrak's urgency levels using defaults,
+// passing through values that were already mapped in the config
+func levelValue(urgency string, defaults map[string]string) string {
+	if v, ok := defaults[urgency]; ok {
+		return v
+	}
+	return urgency
+}
+
+// notifySend shells out to notify-send
+type notifySend struct{}
+
+func (notifySend) Notify(n Notification) error {
+	cmd := exec.Command("notify-send", "-u", n.Urgency, n.Title, n.Body)
+	return cmd.Run()
+}
+
+// dbusNotifier calls org.freedesktop.Notifications directly on the session bus
+type dbusNotifier struct{}
+
+var dbusUrgency = map[string]string{UrgencyLow: "0", UrgencyNormal: "1", UrgencyCritical: "2"}
+
+func (dbusNotifier) Notify(n Notification) error {
+	level, err := strconv.Atoi(levelValue(n.Urgency, dbusUrgency))
+	if err != nil {
+		return fmt.Errorf("invalid urgency %q", n.Urgency)
+	}
+
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return fmt.Errorf("failed to connect to session bus: %w", err)
+	}
+
+	obj := conn.Object("org.freedesktop.Notifications", "/org/freedesktop/Notifications")
+	hints := map[string]dbus.Variant{"urgency": dbus.MakeVariant(byte(level))}
+	call := obj.Call("org.freedesktop.Notifications.Notify", 0,
+		"gametrak", uint32(0), "", n.Title, n.Body, []string{}, hints, int32(-1))
+	return call.Err
+}
+
+// hyprlandNotifier uses Hyprland's own notification overlay. The urgency
+// maps to Hyprland's icon number.
+type hyprlandNotifier struct{}
+
+var hyprlandIcons = map[string]string{UrgencyLow: "2", UrgencyNormal: "1", UrgencyCritical: "3"}
+
+func (hyprlandNotifier) Notify(n Notification) error {
+	icon, err := strconv.Atoi(levelValue(n.Urgency, hyprlandIcons))
+	if err != nil {
+		return fmt.Errorf("invalid urgency %q", n.Urgency)
+	}
+	return hyprland.Notify(icon, 5*time.Second, n.Title+": "+n.Body)
+}
+
+// ntfyNotifier publishes to an ntfy topic URL
+type ntfyNotifier struct {
+	url   string
+	token string
+}
+
+var ntfyPriority = map[string]string{UrgencyLow: "low", UrgencyNormal: "default", UrgencyCritical: "urgent"}
+
+func (b ntfyNotifier) Notify(n Notification) error {
+	req, err := http.NewRequest(http.MethodPost, b.url, strings.NewReader(n.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Title", n.Title)
+	req.Header.Set("Priority", levelValue(n.Urgency, ntfyPriority))
+	if b.token != "" {
+		req.Header.Set("Authorization", "Bearer "+b.token)
+	}
+	return doPush(req)
+}
+
+// gotifyNotifier posts to a Gotify server's message endpoint
+type gotifyNotifier struct {
+	url   string
+	token string
+}
+
+var gotifyPriority = map[string]string{UrgencyLow: "2", UrgencyNormal: "5", UrgencyCritical: "8"}
+
+func (b gotifyNotifier) Notify(n Notification) error {
+	priority, err := strconv.Atoi(levelValue(n.Urgency, gotifyPriority))
+	if err != nil {
+		return fmt.Errorf("invalid urgency %q", n.Urgency)
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"title":    n.Title,
+		"message":  n.Body,
+		"priority": priority,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, b.url+"/message", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", b.token)
+	return doPush(req)
+}
+
+func doPush(req *http.Request) error {
+	client := &http.Client{Timeout: httpTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// stdoutNotifier prints notifications alongside the monitor's output
+type stdoutNotifier struct{}
+
+// console is where the stdout backend prints
+var console io.Writer = os.Stdout
+
+// SetConsole redirects the stdout backend, so the monitor can keep stdout
+// free for machine-readable output
+func SetConsole(w io.Writer) {
+	mu.Lock()
+	console = w
+	mu.Unlock()
+}
+
+func (stdoutNotifier) Notify(n Notification) error {
+	_, err := fmt.Fprintf(console, "[%s] Notification (%s): %s - %s\n", utility.Timestamp(), n.Urgency, n.Title, n.Body)
+	return err
+}
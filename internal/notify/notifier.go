@@ -0,0 +1,138 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/austincgause/gametrak/internal/models"
+)
+
+// Event types that can be routed to backends
+const (
+	EventStarted     = "started"
+	EventGameStarted = "game_started"
+	EventGameEnded   = "game_ended"
+	EventError       = "error"
+	EventReminder    = "reminder"
+	EventMilestone   = "milestone"
+)
+
+// Urgency levels, as understood by notify-send
+const (
+	UrgencyLow      = "low"
+	UrgencyNormal   = "normal"
+	UrgencyCritical = "critical"
+)
+
+// Notification is a message to deliver to one or more backends
+type Notification struct {
+	Event   string
+	Title   string
+	Body    string
+	Urgency string
+}
+
+// Notifier delivers notifications through a single backend
+type Notifier interface {
+	Notify(n Notification) error
+}
+
+// route is a backend together with its urgency mapping
+type route struct {
+	name     string
+	notifier Notifier
+	urgency  map[string]string
+}
+
+var (
+	mu       sync.RWMutex
+	backends = []route{{name: "notify-send", notifier: notifySend{}}}
+	routes   map[string][]string
+)
+
+// Configure replaces the active backends and routes. Backends that fail to
+// initialize are skipped and reported in the returned error; the rest stay
+// usable. With no backends configured, notify-send receives every event.
+func Configure(cfg models.Notifiers) error {
+	if len(cfg.Backends) == 0 {
+		mu.Lock()
+		stopQueues(backends)
+		backends = []route{{name: "notify-send", notifier: notifySend{}}}
+		routes = nil
+		mu.Unlock()
+		return nil
+	}
+
+	var errs []error
+	var configured []route
+
+	names := make([]string, 0, len(cfg.Backends))
+	for name := range cfg.Backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		b := cfg.Backends[name]
+		n, err := newBackend(name, b)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notifier %q: %w", name, err))
+			continue
+		}
+		configured = append(configured, route{name: name, notifier: n, urgency: b.Urgency})
+	}
+
+	for event, targets := range cfg.Routes {
+		for _, target := range targets {
+			if _, ok := cfg.Backends[target]; !ok {
+				errs = append(errs, fmt.Errorf("route %q: unknown notifier %q", event, target))
+			}
+		}
+	}
+
+	mu.Lock()
+	stopQueues(backends)
+	backends = configured
+	routes = cfg.Routes
+	mu.Unlock()
+
+	return errors.Join(errs...)
+}
+
+// dispatch sends a notification to every backend routed for its event,
+// translating the urgency for each one. Push backends only queue it, so
+// their errors are logged when it is sent rather than returned.
+func dispatch(n Notification) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	targets, routed := routes[n.Event]
+
+	var errs []error
+	for _, b := range backends {
+		if routed && !contains(targets, b.name) {
+			continue
+		}
+
+		out := n
+		if mapped, ok := b.urgency[n.Urgency]; ok {
+			out.Urgency = mapped
+		}
+		if err := b.notifier.Notify(out); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", b.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
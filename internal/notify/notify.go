@@ -1,32 +1,11 @@
 package notify
 
-import (
-	"fmt"
-	"os/exec"
-	"time"
-
-	"github.com/austincgause/gametrak/internal/utility"
-)
-
-// GameEnded sends a notification when a game session ends
-func GameEnded(gameName string, duration time.Duration) error {
-	title := "Game Session Ended"
-	body := fmt.Sprintf("%s - %s", gameName, utility.FormatDurationRounded(duration))
-
-	return send(title, body, "normal")
-}
-
-// Error sends a notification when gametrak encounters a fatal error
-func Error(message string) error {
-	return send("Gametrak Error", message, "critical")
-}
-
 // Started sends a notification when gametrak starts monitoring
 func Started() error {
-	return send("Gametrak", "Now tracking game sessions", "low")
+	return Send(EventStarted, "Gametrak", "Now tracking game sessions", UrgencyLow)
 }
 
-func send(title, body, urgency string) error {
-	cmd := exec.Command("notify-send", "-u", urgency, title, body)
-	return cmd.Run()
+// Send delivers a notification to the backends routed for the event type
+func Send(event, title, body, urgency string) error {
+	return dispatch(Notification{Event: event, Title: title, Body: body, Urgency: urgency})
 }
@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// queueSize bounds the notifications waiting for each push backend; more
+// than that are dropped rather than piling up behind an unreachable server
+const queueSize = 16
+
+// flushPoll is how often Flush checks whether the queues have drained
+const flushPoll = 20 * time.Millisecond
+
+// errQueueFull is returned when a push backend's queue has no room left
+var errQueueFull = errors.New("queue full, notification dropped")
+
+// pending counts the notifications queued or being sent by any push backend
+var pending atomic.Int64
+
+// queue sends notifications through a slow backend, such as one pushing over
+// HTTP, from its own goroutine so callers don't wait on the network
+type queue struct {
+	name     string
+	notifier Notifier
+	ch       chan Notification
+}
+
+func newQueue(name string, n Notifier) *queue {
+	q := &queue{name: name, notifier: n, ch: make(chan Notification, queueSize)}
+	go q.run()
+	return q
+}
+
+// Notify queues the notification without waiting for it to be sent
+func (q *queue) Notify(n Notification) error {
+	pending.Add(1)
+	select {
+	case q.ch <- n:
+		return nil
+	default:
+		pending.Add(-1)
+		return errQueueFull
+	}
+}
+
+func (q *queue) run() {
+	for n := range q.ch {
+		if err := q.notifier.Notify(n); err != nil {
+			slog.Warn("failed to send notification", "notifier", q.name, "error", err)
+		}
+		pending.Add(-1)
+	}
+}
+
+// stopQueues ends the goroutines of replaced backends once their queued
+// notifications are sent. The caller holds mu, so nothing is queued meanwhile.
+func stopQueues(routes []route) {
+	for _, r := range routes {
+		if q, ok := r.notifier.(*queue); ok {
+			close(q.ch)
+		}
+	}
+}
+
+// Flush waits for queued notifications to be sent, for at most one push
+// request's timeout. It is called before exiting, which would drop them.
+func Flush() {
+	deadline := time.Now().Add(httpTimeout)
+	for pending.Load() > 0 {
+		if time.Now().After(deadline) {
+			slog.Warn("gave up waiting for notifications to be sent", "pending", pending.Load())
+			return
+		}
+		time.Sleep(flushPoll)
+	}
+}
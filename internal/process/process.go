@@ -0,0 +1,147 @@
+package process
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/austincgause/gametrak/internal/steam"
+)
+
+// procDir is where process information is read from
+const procDir = "/proc"
+
+// Classes returns window classes that could identify a game process, most
+// specific first: steam_app_<id> when Steam started it with a SteamAppId,
+// then its executable names
+func Classes(pid int) []string {
+	var classes []string
+	if id := Environ(pid)["SteamAppId"]; id != "" && id != "0" {
+		classes = append(classes, steam.AppClassPrefix+id)
+	}
+	for _, exe := range Executables(pid) {
+		if !contains(classes, exe) {
+			classes = append(classes, exe)
+		}
+	}
+	return classes
+}
+
+// Descendants lists every process below pid, breadth first
+func Descendants(pid int) []int {
+	children := make(map[int][]int)
+	entries, err := os.ReadDir(procDir)
+	if err != nil {
+		return nil
+	}
+	for _, e := range entries {
+		child, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		if parent, ok := parentPID(child); ok {
+			children[parent] = append(children[parent], child)
+		}
+	}
+
+	var result []int
+	queue := children[pid]
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		result = append(result, p)
+		queue = append(queue, children[p]...)
+	}
+	return result
+}
+
+// parentPID reads a process's parent from /proc/<pid>/stat
+func parentPID(pid int) (int, bool) {
+	fields, ok := stat(pid)
+	if !ok || len(fields) < 2 {
+		return 0, false
+	}
+	ppid, err := strconv.Atoi(fields[1])
+	return ppid, err == nil
+}
+
+// Group returns the process group of a running process. Exited processes
+// that haven't been reaped yet don't count as running.
+func Group(pid int) (int, bool) {
+	fields, ok := stat(pid)
+	if !ok || len(fields) < 3 || fields[0] == "Z" {
+		return 0, false
+	}
+	pgid, err := strconv.Atoi(fields[2])
+	return pgid, err == nil
+}
+
+// stat reads the fields of /proc/<pid>/stat that follow the command name,
+// starting with the state. The name in parentheses may itself contain
+// spaces or parentheses, so fields are counted from the last closing one.
+func stat(pid int) ([]string, bool) {
+	data, err := os.ReadFile(filepath.Join(procDir, strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return nil, false
+	}
+	end := bytes.LastIndexByte(data, ')')
+	if end < 0 {
+		return nil, false
+	}
+	return strings.Fields(string(data[end+1:])), true
+}
+
+// Environ reads a process's environment. It is empty for processes owned
+// by other users.
+func Environ(pid int) map[string]string {
+	data, err := os.ReadFile(filepath.Join(procDir, strconv.Itoa(pid), "environ"))
+	if err != nil {
+		return nil
+	}
+	env := make(map[string]string)
+	for _, kv := range bytes.Split(data, []byte{0}) {
+		if k, v, ok := strings.Cut(string(kv), "="); ok {
+			env[k] = v
+		}
+	}
+	return env
+}
+
+// Executables returns the names a process's window class could take: the
+// basename of argv[0], which for Wine games is the Windows executable, and
+// of the binary actually running
+func Executables(pid int) []string {
+	var names []string
+
+	if data, err := os.ReadFile(filepath.Join(procDir, strconv.Itoa(pid), "cmdline")); err == nil {
+		if argv0, _, _ := bytes.Cut(data, []byte{0}); len(argv0) > 0 {
+			names = append(names, ExeName(string(argv0)))
+		}
+	}
+	if target, err := os.Readlink(filepath.Join(procDir, strconv.Itoa(pid), "exe")); err == nil {
+		names = append(names, ExeName(target))
+	}
+
+	return names
+}
+
+// ExeName takes the basename of a Unix or Windows path. Wine lowercases
+// .exe names for window classes, so they are lowercased here too.
+func ExeName(path string) string {
+	base := filepath.Base(strings.ReplaceAll(path, `\`, "/"))
+	if strings.EqualFold(filepath.Ext(base), ".exe") {
+		return strings.ToLower(base)
+	}
+	return base
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/models"
+)
+
+var dayNames = map[string][]time.Weekday{
+	"sun": {time.Sunday}, "sunday": {time.Sunday},
+	"mon": {time.Monday}, "monday": {time.Monday},
+	"tue": {time.Tuesday}, "tuesday": {time.Tuesday},
+	"wed": {time.Wednesday}, "wednesday": {time.Wednesday},
+	"thu": {time.Thursday}, "thursday": {time.Thursday},
+	"fri": {time.Friday}, "friday": {time.Friday},
+	"sat": {time.Saturday}, "saturday": {time.Saturday},
+	"weekdays": {time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	"weekends": {time.Saturday, time.Sunday},
+	"daily":    {time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday},
+}
+
+// Window is a parsed TimeWindow with its times as minutes since midnight
+type Window struct {
+	models.TimeWindow
+	days     map[time.Weekday]bool
+	from, to int
+}
+
+// Parse validates a TimeWindow and converts it into a Window
+func Parse(tw models.TimeWindow) (Window, error) {
+	w := Window{TimeWindow: tw, days: make(map[time.Weekday]bool)}
+
+	if len(tw.Days) == 0 {
+		for _, d := range dayNames["daily"] {
+			w.days[d] = true
+		}
+	}
+	for _, name := range tw.Days {
+		days, ok := dayNames[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return Window{}, fmt.Errorf("unknown day %q", name)
+		}
+		for _, d := range days {
+			w.days[d] = true
+		}
+	}
+
+	var err error
+	if w.from, err = parseClock(tw.From); err != nil {
+		return Window{}, err
+	}
+	if w.to, err = parseClock(tw.To); err != nil {
+		return Window{}, err
+	}
+	if w.from == w.to {
+		return Window{}, fmt.Errorf("window %s-%s is empty", tw.From, tw.To)
+	}
+
+	return w, nil
+}
+
+// parseClock parses HH:MM into minutes since midnight. 24:00 is accepted as
+// the end of the day.
+func parseClock(s string) (int, error) {
+	h, m, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok {
+		return 0, fmt.Errorf("invalid time %q (want HH:MM)", s)
+	}
+	hours, err1 := strconv.Atoi(h)
+	mins, err2 := strconv.Atoi(m)
+	if err1 != nil || err2 != nil || hours < 0 || mins < 0 || mins > 59 || hours > 24 || (hours == 24 && mins != 0) {
+		return 0, fmt.Errorf("invalid time %q (want HH:MM)", s)
+	}
+	return hours*60 + mins, nil
+}
+
+// wraps reports whether the window runs past midnight
+func (w Window) wraps() bool {
+	return w.to < w.from
+}
+
+// OnDay reports whether the window starts on the given weekday
+func (w Window) OnDay(d time.Weekday) bool {
+	return w.days[d]
+}
+
+// Contains reports whether t falls inside the window, including the part of
+// an overnight window that spills into the following day
+func (w Window) Contains(t time.Time) bool {
+	clock := t.Hour()*60 + t.Minute()
+
+	if !w.wraps() {
+		return w.OnDay(t.Weekday()) && clock >= w.from && clock < w.to
+	}
+
+	if w.OnDay(t.Weekday()) && clock >= w.from {
+		return true
+	}
+	yesterday := t.AddDate(0, 0, -1).Weekday()
+	return w.OnDay(yesterday) && clock < w.to
+}
+
+// String formats the window for display, e.g. "22:00-06:00 (sun, mon)"
+func (w Window) String() string {
+	s := fmt.Sprintf("%s-%s", w.From, w.To)
+	if len(w.Days) > 0 {
+		s += fmt.Sprintf(" (%s)", strings.Join(w.Days, ", "))
+	}
+	return s
+}
+
+// Rules is a parsed Schedule
+type Rules struct {
+	Allow []Window
+	Deny  []Window
+	Mode  string
+}
+
+// Compile parses every window in a schedule. Mode falls back to the given
+// default when the schedule doesn't set one.
+func Compile(s models.Schedule, defaultMode string) (Rules, error) {
+	rules := Rules{Mode: s.Mode}
+	if rules.Mode == "" {
+		rules.Mode = defaultMode
+	}
+	switch rules.Mode {
+	case models.ScheduleWarn, models.ScheduleLog, models.ScheduleClose:
+	default:
+		return Rules{}, fmt.Errorf("invalid schedule mode %q (want warn, log or close)", rules.Mode)
+	}
+
+	for _, tw := range s.Allow {
+		w, err := Parse(tw)
+		if err != nil {
+			return Rules{}, fmt.Errorf("allow rule: %w", err)
+		}
+		rules.Allow = append(rules.Allow, w)
+	}
+	for _, tw := range s.Deny {
+		w, err := Parse(tw)
+		if err != nil {
+			return Rules{}, fmt.Errorf("deny rule: %w", err)
+		}
+		rules.Deny = append(rules.Deny, w)
+	}
+
+	return rules, nil
+}
+
+// Check returns a description of the rule that t violates, or "" if play is
+// permitted at t
+func (r Rules) Check(t time.Time) string {
+	for _, w := range r.Deny {
+		if w.Contains(t) {
+			return "denied " + w.String()
+		}
+	}
+
+	// Allow windows only restrict the days they cover
+	restricted := false
+	for _, w := range r.Allow {
+		if w.Contains(t) {
+			return ""
+		}
+		if w.OnDay(t.Weekday()) {
+			restricted = true
+		}
+	}
+	if restricted {
+		return "outside allowed hours"
+	}
+
+	return ""
+}
+
+// Today returns the allow and deny windows in effect on t's day, including
+// overnight windows carried over from the day before
+func (r Rules) Today(t time.Time) (allow, deny []Window) {
+	for _, w := range r.Allow {
+		if w.activeOn(t) {
+			allow = append(allow, w)
+		}
+	}
+	for _, w := range r.Deny {
+		if w.activeOn(t) {
+			deny = append(deny, w)
+		}
+	}
+	return allow, deny
+}
+
+func (w Window) activeOn(t time.Time) bool {
+	return w.OnDay(t.Weekday()) || (w.wraps() && w.OnDay(t.AddDate(0, 0, -1).Weekday()))
+}
@@ -0,0 +1,131 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/models"
+)
+
+// at returns a time on a weekday of the week starting Sunday 2025-03-02
+func at(day time.Weekday, clock string) time.Time {
+	t, err := time.ParseInLocation("15:04", clock, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return time.Date(2025, 3, 2+int(day), t.Hour(), t.Minute(), 0, 0, time.Local)
+}
+
+func mustParse(t *testing.T, tw models.TimeWindow) Window {
+	t.Helper()
+	w, err := Parse(tw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestContains(t *testing.T) {
+	sameDay := mustParse(t, models.TimeWindow{Days: []string{"weekdays"}, From: "17:00", To: "21:00"})
+	overnight := mustParse(t, models.TimeWindow{Days: []string{"fri", "sat"}, From: "22:00", To: "02:00"})
+	toMidnight := mustParse(t, models.TimeWindow{From: "20:00", To: "24:00"})
+
+	for _, tc := range []struct {
+		name string
+		w    Window
+		at   time.Time
+		want bool
+	}{
+		{"inside", sameDay, at(time.Monday, "18:30"), true},
+		{"at the start", sameDay, at(time.Monday, "17:00"), true},
+		{"at the end", sameDay, at(time.Monday, "21:00"), false},
+		{"wrong day", sameDay, at(time.Saturday, "18:30"), false},
+
+		{"overnight before midnight", overnight, at(time.Friday, "23:00"), true},
+		{"overnight after midnight", overnight, at(time.Saturday, "01:30"), true},
+		{"overnight after midnight on the last day", overnight, at(time.Sunday, "01:30"), true},
+		{"overnight at its end", overnight, at(time.Saturday, "02:00"), false},
+		{"overnight spill from a day it doesn't start on", overnight, at(time.Friday, "01:00"), false},
+		{"overnight evening of a day it doesn't start on", overnight, at(time.Sunday, "23:00"), false},
+
+		{"until midnight", toMidnight, at(time.Wednesday, "23:59"), true},
+		{"after midnight", toMidnight, at(time.Thursday, "00:00"), false},
+	} {
+		if got := tc.w.Contains(tc.at); got != tc.want {
+			t.Errorf("%s: Contains(%s) = %v, want %v", tc.name, tc.at.Format("Mon 15:04"), got, tc.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for name, tw := range map[string]models.TimeWindow{
+		"unknown day":  {Days: []string{"funday"}, From: "10:00", To: "12:00"},
+		"bad clock":    {From: "10", To: "12:00"},
+		"bad minutes":  {From: "10:60", To: "12:00"},
+		"past 24:00":   {From: "10:00", To: "24:01"},
+		"empty window": {From: "10:00", To: "10:00"},
+	} {
+		if _, err := Parse(tw); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	rules, err := Compile(models.Schedule{
+		Allow: []models.TimeWindow{{Days: []string{"weekdays"}, From: "18:00", To: "22:00"}},
+		Deny:  []models.TimeWindow{{From: "23:00", To: "07:00"}},
+	}, models.ScheduleWarn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules.Mode != models.ScheduleWarn {
+		t.Errorf("mode = %q, want the default", rules.Mode)
+	}
+
+	for _, tc := range []struct {
+		at   time.Time
+		want string
+	}{
+		{at(time.Tuesday, "19:00"), ""},
+		{at(time.Tuesday, "15:00"), "outside allowed hours"},
+		// Allow windows don't restrict days they don't cover
+		{at(time.Saturday, "15:00"), ""},
+		// Deny windows apply across midnight, even into allowed days
+		{at(time.Saturday, "23:30"), "denied 23:00-07:00"},
+		{at(time.Monday, "06:30"), "denied 23:00-07:00"},
+	} {
+		if got := rules.Check(tc.at); got != tc.want {
+			t.Errorf("Check(%s) = %q, want %q", tc.at.Format("Mon 15:04"), got, tc.want)
+		}
+	}
+}
+
+func TestToday(t *testing.T) {
+	rules, err := Compile(models.Schedule{
+		Allow: []models.TimeWindow{
+			{Days: []string{"fri"}, From: "20:00", To: "02:00"},
+			{Days: []string{"sun"}, From: "10:00", To: "12:00"},
+		},
+	}, models.ScheduleLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for day, want := range map[time.Weekday]int{
+		time.Friday:   1,
+		time.Saturday: 1, // Friday's window carries over
+		time.Sunday:   1,
+		time.Monday:   0,
+	} {
+		if allow, _ := rules.Today(at(day, "12:00")); len(allow) != want {
+			t.Errorf("%s: %d allow windows, want %d", day, len(allow), want)
+		}
+	}
+}
+
+func TestCompileInvalidMode(t *testing.T) {
+	if _, err := Compile(models.Schedule{Mode: "nag"}, models.ScheduleWarn); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
@@ -0,0 +1,34 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/austincgause/gametrak/internal/models"
+)
+
+// LogViolation appends a schedule violation to the JSONL violations file
+func LogViolation(violationsFile string, v models.ViolationLog) error {
+	if err := os.MkdirAll(filepath.Dir(violationsFile), 0755); err != nil {
+		return fmt.Errorf("failed to create violations directory: %w", err)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal violation: %w", err)
+	}
+
+	f, err := os.OpenFile(violationsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open violations file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write violation: %w", err)
+	}
+
+	return nil
+}
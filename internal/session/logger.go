@@ -5,26 +5,43 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/austincgause/gametrak/internal/models"
 )
 
+// Entry builds the log entry for a session that ended at endTime
+func Entry(session models.Session, endTime time.Time) models.SessionLog {
+	return models.SessionLog{
+		ID:              NewID(),
+		Game:            session.GameName,
+		Class:           session.Class,
+		Start:           session.StartTime.Format(time.RFC3339),
+		End:             endTime.Format(time.RFC3339),
+		DurationSeconds: int64(endTime.Sub(session.StartTime).Seconds()),
+		Source:          session.Source,
+	}
+}
+
 // Log appends a completed session to the JSONL log file
 func Log(sessionsFile string, session models.Session, endTime time.Time) error {
+	return Append(sessionsFile, Entry(session, endTime), nil)
+}
+
+// Append adds an entry to the JSONL log file, waiting for any rewrite in
+// progress to finish. If committed is not nil it runs after the entry is
+// written, while the file is still locked.
+func Append(sessionsFile string, entry models.SessionLog, committed func() error) error {
 	if err := os.MkdirAll(filepath.Dir(sessionsFile), 0755); err != nil {
 		return fmt.Errorf("failed to create sessions directory: %w", err)
 	}
 
-	duration := endTime.Sub(session.StartTime)
-
-	entry := models.SessionLog{
-		Game:            session.GameName,
-		Class:           session.Class,
-		Start:           session.StartTime.Format(time.RFC3339),
-		End:             endTime.Format(time.RFC3339),
-		DurationSeconds: int64(duration.Seconds()),
+	unlock, err := lock(sessionsFile)
+	if err != nil {
+		return err
 	}
+	defer unlock()
 
 	data, err := json.Marshal(entry)
 	if err != nil {
@@ -41,6 +58,10 @@ func Log(sessionsFile string, session models.Session, endTime time.Time) error {
 		return fmt.Errorf("failed to write session: %w", err)
 	}
 
+	if committed != nil {
+		return committed()
+	}
+
 	return nil
 }
 
@@ -54,22 +75,53 @@ func LoadAll(sessionsFile string) ([]models.SessionLog, error) {
 		return nil, fmt.Errorf("failed to read sessions file: %w", err)
 	}
 
+	return parse(data), nil
+}
+
+// parse reads the entries of a JSONL log, giving entries without an ID
+// one derived from their line
+func parse(data []byte) []models.SessionLog {
 	var sessions []models.SessionLog
-	lines := splitLines(data)
+	for _, l := range parseLines(data) {
+		if l.entry != nil {
+			sessions = append(sessions, *l.entry)
+		}
+	}
+	return sessions
+}
+
+// logLine is one line of the log as read. Entry is nil for lines that
+// aren't valid entries, which are kept so a rewrite can preserve them.
+// Derived marks entries whose ID was derived rather than stored.
+type logLine struct {
+	raw     []byte
+	entry   *models.SessionLog
+	derived bool
+}
 
-	for _, line := range lines {
+func parseLines(data []byte) []logLine {
+	var lines []logLine
+	seen := make(map[string]bool)
+
+	for _, line := range splitLines(data) {
 		if len(line) == 0 {
 			continue
 		}
 
 		var entry models.SessionLog
 		if err := json.Unmarshal(line, &entry); err != nil {
-			continue // Skip malformed lines
+			lines = append(lines, logLine{raw: line}) // Malformed, kept as is
+			continue
+		}
+		derived := entry.ID == ""
+		if derived {
+			entry.ID = legacyID(line, seen)
 		}
-		sessions = append(sessions, entry)
+		seen[entry.ID] = true
+		lines = append(lines, logLine{raw: line, entry: &entry, derived: derived})
 	}
 
-	return sessions, nil
+	return lines
 }
 
 func splitLines(data []byte) [][]byte {
@@ -89,3 +141,29 @@ func splitLines(data []byte) [][]byte {
 
 	return lines
 }
+
+// Overlapping returns the other sessions for the same game, compared
+// case-insensitively, whose time span overlaps entry's
+func Overlapping(sessions []models.SessionLog, entry models.SessionLog) []models.SessionLog {
+	start, err1 := time.Parse(time.RFC3339, entry.Start)
+	end, err2 := time.Parse(time.RFC3339, entry.End)
+	if err1 != nil || err2 != nil {
+		return nil
+	}
+
+	var overlaps []models.SessionLog
+	for _, s := range sessions {
+		if s.ID == entry.ID || !strings.EqualFold(s.Game, entry.Game) {
+			continue
+		}
+		sStart, err1 := time.Parse(time.RFC3339, s.Start)
+		sEnd, err2 := time.Parse(time.RFC3339, s.End)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		if start.Before(sEnd) && sStart.Before(end) {
+			overlaps = append(overlaps, s)
+		}
+	}
+	return overlaps
+}
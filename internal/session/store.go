@@ -0,0 +1,260 @@
+package session
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/austincgause/gametrak/internal/models"
+)
+
+// idLength is the number of hex digits in a session ID
+const idLength = 8
+
+// NewID returns a random session ID
+func NewID() string {
+	b := make([]byte, idLength/2)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// legacyID derives an ID for an entry logged before IDs existed from its
+// line, so it stays the same from one load to the next. Identical lines are
+// told apart by how many came before. The ID is saved with the entry the
+// next time the file is rewritten.
+func legacyID(line []byte, seen map[string]bool) string {
+	for n := 0; ; n++ {
+		data := line
+		if n > 0 {
+			data = append(bytes.Clone(line), strconv.Itoa(n)...)
+		}
+		sum := sha256.Sum256(data)
+		id := hex.EncodeToString(sum[:])[:idLength]
+		if !seen[id] {
+			return id
+		}
+	}
+}
+
+// Find returns the index of the session with the given ID or unique ID
+// prefix
+func Find(sessions []models.SessionLog, id string) (int, error) {
+	id = strings.ToLower(id)
+	if id == "" {
+		return -1, fmt.Errorf("no session ID given")
+	}
+
+	found := -1
+	for i, s := range sessions {
+		if s.ID == id {
+			return i, nil
+		}
+		if strings.HasPrefix(s.ID, id) {
+			if found >= 0 {
+				return -1, fmt.Errorf("session ID %s is ambiguous", id)
+			}
+			found = i
+		}
+	}
+	if found < 0 {
+		return -1, fmt.Errorf("no session with ID %s", id)
+	}
+	return found, nil
+}
+
+// Rewrite replaces the log with the result of applying change to its
+// entries. The file is locked throughout, so entries the monitor logs in
+// the meantime wait rather than being lost, and is replaced through a
+// temporary file so readers never see a partial log.
+//
+// Unchanged entries are written back byte for byte, changed ones keep any
+// fields this version doesn't know about, and lines that aren't valid
+// entries stay after the entry they followed.
+//
+// If committed is not nil it runs once the new log is in place, while the
+// file is still locked, so a record of the change can't race another one.
+func Rewrite(sessionsFile string, change func([]models.SessionLog) ([]models.SessionLog, error), committed func() error) error {
+	if err := os.MkdirAll(filepath.Dir(sessionsFile), 0755); err != nil {
+		return fmt.Errorf("failed to create sessions directory: %w", err)
+	}
+
+	unlock, err := lock(sessionsFile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(sessionsFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read sessions file: %w", err)
+	}
+
+	lines := parseLines(data)
+	sessions, err := change(parse(data))
+	if err != nil {
+		return err
+	}
+
+	out, err := encode(lines, sessions)
+	if err != nil {
+		return err
+	}
+	if err := replaceFile(sessionsFile, out); err != nil {
+		return err
+	}
+
+	if committed != nil {
+		return committed()
+	}
+	return nil
+}
+
+// encode writes the rewritten entries, reusing the original lines where it
+// can. Malformed lines are anchored to the entry before them; those whose
+// entry is gone are kept at the end.
+func encode(lines []logLine, sessions []models.SessionLog) ([]byte, error) {
+	originals := make(map[string]logLine)
+	anchored := make(map[string][][]byte)
+	anchor := ""
+	for _, l := range lines {
+		if l.entry == nil {
+			anchored[anchor] = append(anchored[anchor], l.raw)
+			continue
+		}
+		originals[l.entry.ID] = l
+		anchor = l.entry.ID
+	}
+
+	var buf bytes.Buffer
+	writeAnchored := func(id string) {
+		for _, raw := range anchored[id] {
+			buf.Write(raw)
+			buf.WriteByte('\n')
+		}
+		delete(anchored, id)
+	}
+
+	writeAnchored("")
+	for _, s := range sessions {
+		line, err := encodeEntry(s, originals[s.ID])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+		writeAnchored(s.ID)
+	}
+	for _, l := range lines {
+		if l.entry != nil {
+			writeAnchored(l.entry.ID)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeEntry marshals an entry. An unchanged entry keeps its original
+// line, and a changed one keeps the original's unknown fields. Derived IDs
+// are saved, since removing an identical line would change them.
+func encodeEntry(s models.SessionLog, original logLine) ([]byte, error) {
+	if original.entry == nil {
+		return marshalEntry(s)
+	}
+	if !original.derived && reflect.DeepEqual(*original.entry, s) {
+		return original.raw, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(original.raw, &fields); err != nil {
+		return marshalEntry(s)
+	}
+	for _, key := range entryKeys {
+		delete(fields, key)
+	}
+
+	known, err := marshalEntry(s)
+	if err != nil || len(fields) == 0 {
+		return known, err
+	}
+	if err := json.Unmarshal(known, &fields); err != nil {
+		return nil, fmt.Errorf("failed to marshal session: %w", err)
+	}
+	line, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal session: %w", err)
+	}
+	return line, nil
+}
+
+func marshalEntry(s models.SessionLog) ([]byte, error) {
+	line, err := json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal session: %w", err)
+	}
+	return line, nil
+}
+
+// entryKeys are the JSON keys of the fields SessionLog knows about
+var entryKeys = func() []string {
+	var keys []string
+	t := reflect.TypeOf(models.SessionLog{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		keys = append(keys, name)
+	}
+	return keys
+}()
+
+// replaceFile writes data to a temporary file, syncs it and renames it
+// over path, so a crash leaves either the old file or the new one
+func replaceFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write sessions file: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write sessions file: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to sync sessions file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write sessions file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace sessions file: %w", err)
+	}
+	return nil
+}
+
+// lock takes an exclusive lock guarding the sessions file. The lock lives
+// on a separate file, since rewriting replaces the log itself.
+func lock(sessionsFile string) (func(), error) {
+	f, err := os.OpenFile(sessionsFile+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sessions lock: %w", err)
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock sessions file: %w", err)
+	}
+	return func() {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
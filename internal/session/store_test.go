@@ -0,0 +1,198 @@
+package session
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/models"
+)
+
+const (
+	lineHades    = `{"id":"aaaa1111","game":"Hades","class":"hades.exe","start":"2025-01-01T20:00:00Z","end":"2025-01-01T21:00:00Z","duration_seconds":3600,"mood":"great"}`
+	lineFactorio = `{"game":"Factorio","class":"factorio","start":"2025-01-02T20:00:00Z","end":"2025-01-02T20:30:00Z","duration_seconds":1800,"id":"bbbb2222"}`
+	lineBroken   = `{"game":"Celeste","start":`
+)
+
+// writeLog creates a sessions file holding lines
+func writeLog(t *testing.T, lines ...string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "sessions.jsonl")
+	if err := os.WriteFile(file, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func readLines(t *testing.T, file string) []string {
+	t.Helper()
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func TestRewriteKeepsLines(t *testing.T) {
+	file := writeLog(t, lineHades, lineBroken, lineFactorio)
+
+	err := Rewrite(file, func(sessions []models.SessionLog) ([]models.SessionLog, error) {
+		if len(sessions) != 2 {
+			t.Fatalf("change got %d sessions, want the 2 valid ones", len(sessions))
+		}
+		sessions[0].Game = "Hades II"
+		return sessions, nil
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := readLines(t, file)
+	if len(lines) != 3 {
+		t.Fatalf("log has %d lines, want 3:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	// The edited entry keeps the field this version doesn't know
+	if !strings.Contains(lines[0], `"game":"Hades II"`) || !strings.Contains(lines[0], `"mood":"great"`) {
+		t.Errorf("edited line = %s", lines[0])
+	}
+	// The malformed line stays after the entry it followed
+	if lines[1] != lineBroken {
+		t.Errorf("line 2 = %s, want the malformed line", lines[1])
+	}
+	// Unchanged entries are written back byte for byte
+	if lines[2] != lineFactorio {
+		t.Errorf("unchanged line = %s, want %s", lines[2], lineFactorio)
+	}
+}
+
+func TestRewriteRemovingAnchor(t *testing.T) {
+	file := writeLog(t, lineHades, lineBroken, lineFactorio)
+
+	err := Rewrite(file, func(sessions []models.SessionLog) ([]models.SessionLog, error) {
+		return sessions[1:], nil
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A malformed line whose entry is gone is kept at the end
+	want := []string{lineFactorio, lineBroken}
+	if lines := readLines(t, file); strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("log = %q, want %q", lines, want)
+	}
+}
+
+func TestRewriteSavesDerivedIDs(t *testing.T) {
+	legacy := `{"game":"Factorio","class":"factorio","start":"2025-01-02T20:00:00Z","end":"2025-01-02T20:30:00Z","duration_seconds":1800}`
+	file := writeLog(t, legacy, legacy)
+
+	before, err := LoadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(before) != 2 || before[0].ID == "" || before[0].ID == before[1].ID {
+		t.Fatalf("identical legacy lines got IDs %q and %q", before[0].ID, before[1].ID)
+	}
+
+	unchanged := func(sessions []models.SessionLog) ([]models.SessionLog, error) { return sessions, nil }
+	if err := Rewrite(file, unchanged, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := LoadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, line := range readLines(t, file) {
+		if !strings.Contains(line, `"id":"`+before[i].ID+`"`) {
+			t.Errorf("line %d = %s, want derived ID %s saved", i+1, line, before[i].ID)
+		}
+		if after[i].ID != before[i].ID {
+			t.Errorf("ID %d changed from %s to %s", i+1, before[i].ID, after[i].ID)
+		}
+	}
+}
+
+func TestRewriteChangeError(t *testing.T) {
+	file := writeLog(t, lineHades, lineBroken)
+	failed := errors.New("no such session")
+
+	committed := false
+	err := Rewrite(file, func([]models.SessionLog) ([]models.SessionLog, error) {
+		return nil, failed
+	}, func() error {
+		committed = true
+		return nil
+	})
+	if !errors.Is(err, failed) {
+		t.Errorf("error = %v, want the change's error", err)
+	}
+	if committed {
+		t.Error("committed ran after a failed change")
+	}
+	if lines := readLines(t, file); len(lines) != 2 || lines[0] != lineHades {
+		t.Errorf("log changed after a failed change: %q", lines)
+	}
+}
+
+func TestRewriteLocksOutAppend(t *testing.T) {
+	file := writeLog(t, lineFactorio)
+
+	inside := make(chan struct{})
+	release := make(chan struct{})
+	rewritten := make(chan error)
+	go func() {
+		rewritten <- Rewrite(file, func(sessions []models.SessionLog) ([]models.SessionLog, error) {
+			close(inside)
+			<-release
+			return sessions, nil
+		}, nil)
+	}()
+	<-inside
+
+	appended := make(chan error)
+	go func() {
+		appended <- Append(file, models.SessionLog{ID: "cccc3333", Game: "Celeste"}, nil)
+	}()
+
+	select {
+	case err := <-appended:
+		t.Fatalf("Append finished during a rewrite: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-rewritten; err != nil {
+		t.Fatal(err)
+	}
+	if err := <-appended; err != nil {
+		t.Fatal(err)
+	}
+
+	// The entry logged during the rewrite wasn't overwritten
+	sessions, err := LoadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 || sessions[1].ID != "cccc3333" {
+		t.Errorf("sessions = %+v, want Factorio then Celeste", sessions)
+	}
+}
+
+func TestFind(t *testing.T) {
+	sessions := []models.SessionLog{{ID: "aaaa1111"}, {ID: "aaab2222"}, {ID: "b0000000"}}
+
+	for id, want := range map[string]int{"aaaa1111": 0, "AAAB": 1, "b": 2} {
+		if i, err := Find(sessions, id); err != nil || i != want {
+			t.Errorf("Find(%q) = %d, %v; want %d", id, i, err, want)
+		}
+	}
+	for _, id := range []string{"aaa", "c", ""} {
+		if i, err := Find(sessions, id); err == nil {
+			t.Errorf("Find(%q) = %d, want an error", id, i)
+		}
+	}
+}
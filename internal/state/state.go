@@ -0,0 +1,112 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/austincgause/gametrak/internal/models"
+)
+
+// ActiveSession is a session the monitor is currently tracking
+type ActiveSession struct {
+	Game    string `json:"game"`
+	Class   string `json:"class"`
+	Title   string `json:"title,omitempty"`
+	Address string `json:"address"`
+	Start   string `json:"start"`
+}
+
+// Snapshot is the monitor's live state as published in the state file
+type Snapshot struct {
+	PID      int             `json:"pid"`
+	Updated  string          `json:"updated"`
+	Sessions []ActiveSession `json:"sessions"`
+}
+
+// New builds a snapshot of the given active sessions, oldest first
+func New(sessions map[string]*models.Session) Snapshot {
+	snap := Snapshot{
+		PID:      os.Getpid(),
+		Updated:  time.Now().Format(time.RFC3339),
+		Sessions: []ActiveSession{},
+	}
+	for _, s := range sessions {
+		snap.Sessions = append(snap.Sessions, ActiveSession{
+			Game:    s.GameName,
+			Class:   s.Class,
+			Title:   s.Title,
+			Address: s.Address,
+			Start:   s.StartTime.Format(time.RFC3339),
+		})
+	}
+	sort.Slice(snap.Sessions, func(i, j int) bool {
+		return snap.Sessions[i].Start < snap.Sessions[j].Start
+	})
+	return snap
+}
+
+// Write replaces the state file with the given active sessions. The file is
+// written to a temporary path first so readers never see a partial snapshot.
+func Write(stateFile string, sessions map[string]*models.Session) error {
+	if err := os.MkdirAll(filepath.Dir(stateFile), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.Marshal(New(sessions))
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	tmp := stateFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmp, stateFile); err != nil {
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+
+	return nil
+}
+
+// Read loads the monitor's live state. It returns ok=false if no monitor is
+// running, including when a stale file was left behind by a crashed one.
+func Read(stateFile string) (snap Snapshot, ok bool, err error) {
+	data, err := os.ReadFile(stateFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Snapshot{}, false, nil
+		}
+		return Snapshot{}, false, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return Snapshot{}, false, fmt.Errorf("failed to parse state file: %w", err)
+	}
+
+	if !processAlive(snap.PID) {
+		return Snapshot{}, false, nil
+	}
+
+	return snap, true, nil
+}
+
+// Remove deletes the state file when the monitor shuts down
+func Remove(stateFile string) error {
+	if err := os.Remove(stateFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove state file: %w", err)
+	}
+	return nil
+}
+
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
@@ -0,0 +1,118 @@
+package steam
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/adrg/xdg"
+)
+
+// AppClassPrefix is the window class prefix Steam's Proton games get
+const AppClassPrefix = "steam_app_"
+
+// AppID extracts the numeric app ID from a steam_app_<id> window class
+func AppID(class string) (string, bool) {
+	id, ok := strings.CutPrefix(class, AppClassPrefix)
+	if !ok || id == "" {
+		return "", false
+	}
+	for _, c := range id {
+		if c < '0' || c > '9' {
+			return "", false
+		}
+	}
+	return id, true
+}
+
+// FindRoot returns the first Steam installation found in the usual native
+// and Flatpak locations
+func FindRoot() (string, bool) {
+	candidates := []string{
+		filepath.Join(xdg.DataHome, "Steam"),
+		filepath.Join(xdg.Home, ".steam", "steam"),
+		filepath.Join(xdg.Home, ".steam", "root"),
+		filepath.Join(xdg.Home, ".var", "app", "com.valvesoftware.Steam", ".local", "share", "Steam"),
+	}
+	for _, dir := range candidates {
+		if _, err := os.Stat(filepath.Join(dir, "steamapps")); err == nil {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+// Libraries lists the Steam library folders from root's libraryfolders.vdf.
+// The root itself is always the first library.
+func Libraries(root string) ([]string, error) {
+	libraries := []string{root}
+
+	data, err := os.ReadFile(filepath.Join(root, "steamapps", "libraryfolders.vdf"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return libraries, nil
+		}
+		return libraries, fmt.Errorf("failed to read libraryfolders.vdf: %w", err)
+	}
+
+	kv, err := ParseVDF(string(data))
+	if err != nil {
+		return libraries, fmt.Errorf("failed to parse libraryfolders.vdf: %w", err)
+	}
+
+	folders, ok := kv.Object("libraryfolders")
+	if !ok {
+		return libraries, nil
+	}
+
+	keys := make([]string, 0, len(folders))
+	for k := range folders {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	seen := map[string]bool{filepath.Clean(root): true}
+	for _, k := range keys {
+		v := folders[k]
+		var path string
+		switch f := v.(type) {
+		case KeyValues:
+			path, _ = f.Get("path")
+		case string:
+			// Older files list paths directly
+			path = f
+		}
+		if path == "" || !filepath.IsAbs(path) || seen[filepath.Clean(path)] {
+			continue
+		}
+		seen[filepath.Clean(path)] = true
+		libraries = append(libraries, path)
+	}
+
+	return libraries, nil
+}
+
+// ManifestName reads the game name from an appmanifest_<id>.acf file
+func ManifestName(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	kv, err := ParseVDF(string(data))
+	if err != nil {
+		return "", err
+	}
+
+	state, ok := kv.Object("AppState")
+	if !ok {
+		return "", fmt.Errorf("%s has no AppState", filepath.Base(path))
+	}
+	name, ok := state.Get("name")
+	if !ok || name == "" {
+		return "", fmt.Errorf("%s has no name", filepath.Base(path))
+	}
+	return name, nil
+}
@@ -0,0 +1,67 @@
+package steam
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAppID(t *testing.T) {
+	for class, want := range map[string]string{
+		"steam_app_620":     "620",
+		"steam_app_1145360": "1145360",
+		"steam_app_":        "",
+		"steam_app_62x":     "",
+		"steam":             "",
+		"factorio":          "",
+	} {
+		got, ok := AppID(class)
+		if got != want || ok != (want != "") {
+			t.Errorf("AppID(%q) = %q, %v; want %q", class, got, ok, want)
+		}
+	}
+}
+
+func TestLibraries(t *testing.T) {
+	root := filepath.Join("testdata", "steam")
+	libraries, err := Libraries(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Relative and repeated paths are skipped
+	want := []string{
+		root,
+		"/home/player/.local/share/Steam",
+		"/mnt/games/SteamLibrary",
+		"/mnt/old/SteamLibrary",
+	}
+	if !reflect.DeepEqual(libraries, want) {
+		t.Errorf("Libraries = %q, want %q", libraries, want)
+	}
+}
+
+func TestLibrariesWithoutFolders(t *testing.T) {
+	root := filepath.Join("testdata", "library")
+	libraries, err := Libraries(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(libraries, []string{root}) {
+		t.Errorf("Libraries = %q, want only the root", libraries)
+	}
+}
+
+func TestManifestName(t *testing.T) {
+	name, err := ManifestName(filepath.Join("testdata", "steam", "steamapps", "appmanifest_620.acf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "Portal 2" {
+		t.Errorf("name = %q, want Portal 2", name)
+	}
+
+	if _, err := ManifestName(filepath.Join("testdata", "library", "steamapps", "appmanifest_404.acf")); err == nil {
+		t.Error("expected an error for a manifest without a name")
+	}
+}
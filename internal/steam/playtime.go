@@ -0,0 +1,64 @@
+package steam
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Playtime reads the minutes played per app ID from every account's
+// localconfig.vdf under root's userdata, summed across accounts
+func Playtime(root string) (map[string]int, error) {
+	configs, err := filepath.Glob(filepath.Join(root, "userdata", "*", "config", "localconfig.vdf"))
+	if err != nil {
+		return nil, err
+	}
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("no Steam user data found in %s", root)
+	}
+
+	minutes := make(map[string]int)
+	for _, path := range configs {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		kv, err := ParseVDF(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+
+		apps, ok := objectPath(kv, "UserLocalConfigStore", "Software", "Valve", "Steam", "apps")
+		if !ok {
+			continue
+		}
+		for id, v := range apps {
+			app, ok := v.(KeyValues)
+			if !ok {
+				continue
+			}
+			played, ok := app.Get("Playtime")
+			if !ok {
+				continue
+			}
+			if n, err := strconv.Atoi(played); err == nil && n > 0 {
+				minutes[id] += n
+			}
+		}
+	}
+
+	return minutes, nil
+}
+
+func objectPath(kv KeyValues, keys ...string) (KeyValues, bool) {
+	for _, key := range keys {
+		next, ok := kv.Object(key)
+		if !ok {
+			return nil, false
+		}
+		kv = next
+	}
+	return kv, true
+}
@@ -0,0 +1,107 @@
+package steam
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Resolver maps Steam app IDs to game names using the local libraries'
+// app manifests. Names are cached in memory and in a JSON file, so each
+// app is only looked up once. IDs that aren't installed are not cached,
+// since they may be installed later.
+type Resolver struct {
+	root      string
+	cacheFile string
+	names     map[string]string
+}
+
+// Root returns the Steam installation the resolver reads, or "" if none
+// was found
+func (r *Resolver) Root() string {
+	return r.root
+}
+
+// NewResolver creates a resolver for the Steam installation at root, or the
+// first one found if root is empty. The cache file is loaded if it exists.
+func NewResolver(root, cacheFile string) *Resolver {
+	if root == "" {
+		root, _ = FindRoot()
+	}
+
+	r := &Resolver{root: root, cacheFile: cacheFile, names: make(map[string]string)}
+	if data, err := os.ReadFile(cacheFile); err == nil {
+		json.Unmarshal(data, &r.names)
+	}
+	return r
+}
+
+// Name returns the game name for a steam_app_<id> window class. A nil
+// *Resolver never resolves anything.
+func (r *Resolver) Name(class string) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+
+	id, ok := AppID(class)
+	if !ok {
+		return "", false
+	}
+	return r.NameForID(id)
+}
+
+// NameForID returns the game name for a Steam app ID
+func (r *Resolver) NameForID(id string) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+	if name, ok := r.names[id]; ok {
+		return name, true
+	}
+
+	name, err := r.lookup(id)
+	if err != nil {
+		return "", false
+	}
+
+	r.names[id] = name
+	r.save()
+	return name, true
+}
+
+// lookup searches every library for the app's manifest
+func (r *Resolver) lookup(id string) (string, error) {
+	if r.root == "" {
+		return "", fmt.Errorf("no Steam installation found")
+	}
+
+	libraries, _ := Libraries(r.root)
+	for _, lib := range libraries {
+		manifest := filepath.Join(lib, "steamapps", "appmanifest_"+id+".acf")
+		if _, err := os.Stat(manifest); err != nil {
+			continue
+		}
+		return ManifestName(manifest)
+	}
+	return "", fmt.Errorf("app %s is not installed", id)
+}
+
+// save writes the cache, ignoring failures since it can always be rebuilt
+func (r *Resolver) save() {
+	if r.cacheFile == "" {
+		return
+	}
+	data, err := json.MarshalIndent(r.names, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(r.cacheFile), 0755); err != nil {
+		return
+	}
+	tmp := r.cacheFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return
+	}
+	os.Rename(tmp, r.cacheFile)
+}
@@ -0,0 +1,101 @@
+package steam
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testRoot returns a Steam root holding the testdata/steam manifests, with
+// a libraryfolders.vdf listing itself and the testdata/library library
+func testRoot(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	steamapps := filepath.Join(root, "steamapps")
+	if err := os.MkdirAll(steamapps, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := os.ReadFile(filepath.Join("testdata", "steam", "steamapps", "appmanifest_620.acf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(steamapps, "appmanifest_620.acf"), manifest, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	library, err := filepath.Abs(filepath.Join("testdata", "library"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	folders := `"libraryfolders"
+{
+	"0"	{ "path" ` + quote(root) + ` }
+	"1"	{ "path" ` + quote(library) + ` }
+}
+`
+	if err := os.WriteFile(filepath.Join(steamapps, "libraryfolders.vdf"), []byte(folders), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func quote(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+func TestResolverName(t *testing.T) {
+	root := testRoot(t)
+	cache := filepath.Join(t.TempDir(), "steam.json")
+	r := NewResolver(root, cache)
+
+	for class, want := range map[string]string{
+		"steam_app_620":     "Portal 2", // in the root library
+		"steam_app_1145360": "Hades",    // in another library
+	} {
+		if name, ok := r.Name(class); !ok || name != want {
+			t.Errorf("Name(%q) = %q, %v; want %q", class, name, ok, want)
+		}
+	}
+
+	for _, class := range []string{"steam_app_228980", "steam_app_404", "factorio"} {
+		if name, ok := r.Name(class); ok {
+			t.Errorf("Name(%q) = %q, want no name", class, name)
+		}
+	}
+}
+
+func TestResolverCache(t *testing.T) {
+	cache := filepath.Join(t.TempDir(), "steam.json")
+	NewResolver(testRoot(t), cache).Name("steam_app_620")
+
+	var names map[string]string
+	data, err := os.ReadFile(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &names); err != nil {
+		t.Fatal(err)
+	}
+	if names["620"] != "Portal 2" || len(names) != 1 {
+		t.Errorf("cache = %v, want only Portal 2", names)
+	}
+
+	// A cached name resolves without the installation
+	r := NewResolver(filepath.Join(t.TempDir(), "missing"), cache)
+	if name, ok := r.Name("steam_app_620"); !ok || name != "Portal 2" {
+		t.Errorf("cached Name = %q, %v; want Portal 2", name, ok)
+	}
+	if _, ok := r.Name("steam_app_1145360"); ok {
+		t.Error("resolved an uncached app without an installation")
+	}
+}
+
+func TestNilResolver(t *testing.T) {
+	var r *Resolver
+	if _, ok := r.Name("steam_app_620"); ok {
+		t.Error("nil resolver resolved a name")
+	}
+}
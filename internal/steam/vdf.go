@@ -0,0 +1,201 @@
+package steam
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KeyValues is a parsed Valve KeyValues (VDF) object. Values are either
+// strings or nested KeyValues. Keys are matched case-insensitively by Get,
+// as Steam itself does.
+type KeyValues map[string]any
+
+// Get returns the string value for a key
+func (kv KeyValues) Get(key string) (string, bool) {
+	v, ok := kv.lookup(key)
+	if !ok {
+		return "", false
+	}
+	s, ok := v.(string)
+	return s, ok
+}
+
+// Object returns the nested object for a key
+func (kv KeyValues) Object(key string) (KeyValues, bool) {
+	v, ok := kv.lookup(key)
+	if !ok {
+		return nil, false
+	}
+	obj, ok := v.(KeyValues)
+	return obj, ok
+}
+
+func (kv KeyValues) lookup(key string) (any, bool) {
+	if v, ok := kv[key]; ok {
+		return v, true
+	}
+	for k, v := range kv {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// ParseVDF parses text VDF, as used by Steam's .vdf and .acf files
+func ParseVDF(data string) (KeyValues, error) {
+	p := &vdfParser{src: data}
+	kv, err := p.object(false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VDF at line %d: %w", p.line+1, err)
+	}
+	return kv, nil
+}
+
+type vdfParser struct {
+	src  string
+	pos  int
+	line int
+}
+
+// token kinds
+const (
+	tokEOF = iota
+	tokString
+	tokOpen
+	tokClose
+)
+
+// object reads key/value pairs until a closing brace, or the end of input
+// at the top level
+func (p *vdfParser) object(nested bool) (KeyValues, error) {
+	kv := KeyValues{}
+	for {
+		kind, key, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case tokEOF:
+			if nested {
+				return nil, fmt.Errorf("unexpected end of input")
+			}
+			return kv, nil
+		case tokClose:
+			if !nested {
+				return nil, fmt.Errorf("unexpected }")
+			}
+			return kv, nil
+		case tokOpen:
+			return nil, fmt.Errorf("unexpected {")
+		}
+
+		kind, value, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case tokString:
+			kv[key] = value
+		case tokOpen:
+			child, err := p.object(true)
+			if err != nil {
+				return nil, err
+			}
+			kv[key] = child
+		default:
+			return nil, fmt.Errorf("missing value for %q", key)
+		}
+
+		p.skipConditional()
+	}
+}
+
+func (p *vdfParser) next() (kind int, text string, err error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return tokEOF, "", nil
+	}
+
+	switch c := p.src[p.pos]; c {
+	case '{':
+		p.pos++
+		return tokOpen, "", nil
+	case '}':
+		p.pos++
+		return tokClose, "", nil
+	case '"':
+		s, err := p.quoted()
+		return tokString, s, err
+	default:
+		start := p.pos
+		for p.pos < len(p.src) && !strings.ContainsRune(" \t\r\n{}\"", rune(p.src[p.pos])) {
+			p.pos++
+		}
+		return tokString, p.src[start:p.pos], nil
+	}
+}
+
+func (p *vdfParser) quoted() (string, error) {
+	p.pos++ // opening quote
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '"':
+			p.pos++
+			return b.String(), nil
+		case c == '\\' && p.pos+1 < len(p.src):
+			p.pos++
+			switch e := p.src[p.pos]; e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(e)
+			}
+		default:
+			if c == '\n' {
+				p.line++
+			}
+			b.WriteByte(c)
+		}
+		p.pos++
+	}
+	return "", fmt.Errorf("unterminated string")
+}
+
+// skipSpace skips whitespace and // comments
+func (p *vdfParser) skipSpace() {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '\n':
+			p.line++
+			p.pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case strings.HasPrefix(p.src[p.pos:], "//"):
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// skipConditional skips a platform conditional such as [$WIN32] after a
+// value; gametrak doesn't evaluate them
+func (p *vdfParser) skipConditional() {
+	save := p.pos
+	p.skipSpace()
+	if p.pos < len(p.src) && p.src[p.pos] == '[' {
+		if end := strings.IndexByte(p.src[p.pos:], ']'); end >= 0 {
+			p.pos += end + 1
+			return
+		}
+	}
+	p.pos = save
+}
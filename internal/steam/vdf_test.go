@@ -0,0 +1,120 @@
+package steam
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseVDF(t *testing.T) {
+	kv, err := ParseVDF(`// comment before the root
+"Root"
+{
+	"name"		"Portal 2"
+	unquoted	value
+	"nested"
+	{
+		"deeper"	{ "key" "value" }
+		"empty"		{}
+	}
+	"launch"	"-novid"	[$WIN32]
+	"after"		"conditional"
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, ok := kv.Object("Root")
+	if !ok {
+		t.Fatalf("no Root object in %v", kv)
+	}
+	for key, want := range map[string]string{
+		"name":     "Portal 2",
+		"unquoted": "value",
+		"launch":   "-novid",
+		"after":    "conditional",
+	} {
+		if got, ok := root.Get(key); !ok || got != want {
+			t.Errorf("%s = %q, %v; want %q", key, got, ok, want)
+		}
+	}
+
+	nested, ok := root.Object("nested")
+	if !ok {
+		t.Fatal("no nested object")
+	}
+	deeper, ok := nested.Object("deeper")
+	if !ok {
+		t.Fatal("no nested/deeper object")
+	}
+	if got, _ := deeper.Get("key"); got != "value" {
+		t.Errorf("nested/deeper/key = %q, want value", got)
+	}
+	if empty, ok := nested.Object("empty"); !ok || len(empty) != 0 {
+		t.Errorf("nested/empty = %v, %v; want an empty object", empty, ok)
+	}
+
+	if _, ok := root.Get("nested"); ok {
+		t.Error("Get returned an object as a string")
+	}
+	if _, ok := root.Object("name"); ok {
+		t.Error("Object returned a string as an object")
+	}
+}
+
+func TestParseVDFQuoting(t *testing.T) {
+	kv, err := ParseVDF(`"key with spaces" "value with spaces"
+"escaped \"quotes\"" "C:\\Program Files\\Steam"
+"controls" "line\tone\nline two"
+"{braces}" "}{"
+"" "empty key"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]string{
+		"key with spaces":  "value with spaces",
+		`escaped "quotes"`: `C:\Program Files\Steam`,
+		"controls":         "line\tone\nline two",
+		"{braces}":         "}{",
+		"":                 "empty key",
+	} {
+		if got, ok := kv.Get(key); !ok || got != want {
+			t.Errorf("%q = %q, %v; want %q", key, got, ok, want)
+		}
+	}
+}
+
+func TestParseVDFCaseInsensitiveKeys(t *testing.T) {
+	kv, err := ParseVDF(`"AppState" { "Name" "Hades" }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, ok := kv.Object("appstate")
+	if !ok {
+		t.Fatal("appstate not found")
+	}
+	if name, _ := state.Get("NAME"); name != "Hades" {
+		t.Errorf("NAME = %q, want Hades", name)
+	}
+}
+
+func TestParseVDFErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"unterminated string": `"key" "value`,
+		"unclosed object":     `"root" { "key" "value"`,
+		"stray close":         `"key" "value" }`,
+		"stray open":          `{ "key" "value" }`,
+		"missing value":       `"root" { "key" }`,
+	} {
+		if _, err := ParseVDF(data); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	_, err := ParseVDF("\"root\"\n{\n\t\"key\"\n}\n")
+	if err == nil || !strings.Contains(err.Error(), "line 4") {
+		t.Errorf("error = %v, want it to point at line 4", err)
+	}
+}
@@ -0,0 +1,64 @@
+package systemd
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Notify sends a state update to the service manager over $NOTIFY_SOCKET,
+// as described in sd_notify(3). It does nothing when not run by systemd.
+func Notify(state string) error {
+	path := os.Getenv("NOTIFY_SOCKET")
+	if path == "" {
+		return nil
+	}
+	// A leading @ names a socket in the abstract namespace
+	if path[0] == '@' {
+		path = "\x00" + path[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// Ready tells systemd the service has finished starting up
+func Ready(status string) error {
+	return Notify("READY=1\nSTATUS=" + status)
+}
+
+// Status sets the one-line status shown by systemctl status
+func Status(status string) error {
+	return Notify("STATUS=" + status)
+}
+
+// Stopping tells systemd the service is shutting down
+func Stopping() error {
+	return Notify("STOPPING=1")
+}
+
+// Watchdog resets the service's watchdog timer
+func Watchdog() error {
+	return Notify("WATCHDOG=1")
+}
+
+// WatchdogInterval returns how often to send watchdog pings: half the
+// timeout systemd expects, per sd_watchdog_enabled(3). ok is false when the
+// watchdog isn't enabled for this process.
+func WatchdogInterval() (interval time.Duration, ok bool) {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, false
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, false
+	}
+	return time.Duration(usec) * time.Microsecond / 2, true
+}
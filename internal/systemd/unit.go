@@ -0,0 +1,86 @@
+package systemd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/adrg/xdg"
+)
+
+// UnitName is the name of gametrak's user service
+const UnitName = "gametrak.service"
+
+// UnitPath is where the user unit is installed
+var UnitPath = filepath.Join(xdg.ConfigHome, "systemd", "user", UnitName)
+
+// Unit renders the user unit that runs the monitor with the given command
+// line. The service is tied to the graphical session, since it needs
+// Hyprland's sockets, and systemd restarts it if it stops answering the
+// watchdog.
+func Unit(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = quoteArg(a)
+	}
+
+	return fmt.Sprintf(`[Unit]
+Description=Gametrak game session tracker
+Documentation=https://github.com/austincgause/gametrak
+PartOf=graphical-session.target
+After=graphical-session.target
+Requisite=graphical-session.target
+
+[Service]
+Type=notify
+ExecStart=%s
+Restart=on-failure
+RestartSec=5
+WatchdogSec=60
+
+[Install]
+WantedBy=graphical-session.target
+`, strings.Join(quoted, " "))
+}
+
+// Install writes the unit file, replacing any existing one
+func Install(args []string) error {
+	if err := os.MkdirAll(filepath.Dir(UnitPath), 0755); err != nil {
+		return fmt.Errorf("failed to create unit directory: %w", err)
+	}
+	if err := os.WriteFile(UnitPath, []byte(Unit(args)), 0644); err != nil {
+		return fmt.Errorf("failed to write unit file: %w", err)
+	}
+	return nil
+}
+
+// Uninstall removes the unit file if it exists
+func Uninstall() error {
+	if err := os.Remove(UnitPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove unit file: %w", err)
+	}
+	return nil
+}
+
+// Systemctl runs systemctl --user with the given arguments, passing its
+// output through
+func Systemctl(args ...string) error {
+	cmd := exec.Command("systemctl", append([]string{"--user"}, args...)...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("systemctl --user %s: %w", strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+// quoteArg quotes a command line argument for ExecStart when needed
+func quoteArg(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\"'\\$%") {
+		return s
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `$$`, `%`, `%%`)
+	return `"` + r.Replace(s) + `"`
+}
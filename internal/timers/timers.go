@@ -0,0 +1,67 @@
+package timers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/austincgause/gametrak/internal/models"
+)
+
+// Load reads the running manual timers. A missing file means none.
+func Load(timersFile string) ([]models.Timer, error) {
+	data, err := os.ReadFile(timersFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read timers file: %w", err)
+	}
+
+	var timers []models.Timer
+	if err := json.Unmarshal(data, &timers); err != nil {
+		return nil, fmt.Errorf("failed to parse timers file: %w", err)
+	}
+	return timers, nil
+}
+
+// Save replaces the timers file, removing it once no timers are running
+func Save(timersFile string, timers []models.Timer) error {
+	if len(timers) == 0 {
+		if err := os.Remove(timersFile); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove timers file: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(timersFile), 0755); err != nil {
+		return fmt.Errorf("failed to create timers directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(timers, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal timers: %w", err)
+	}
+
+	tmp := timersFile + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write timers file: %w", err)
+	}
+	if err := os.Rename(tmp, timersFile); err != nil {
+		return fmt.Errorf("failed to replace timers file: %w", err)
+	}
+	return nil
+}
+
+// Find returns the index of the timer for a game, compared
+// case-insensitively, or -1 if there is none
+func Find(timers []models.Timer, game string) int {
+	for i, t := range timers {
+		if strings.EqualFold(t.Game, game) {
+			return i
+		}
+	}
+	return -1
+}
@@ -2,6 +2,7 @@ package utility
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -60,6 +61,22 @@ func FormatDurationRounded(d time.Duration) string {
 	return fmt.Sprintf("%d %s %d mins", hours, hourWord, mins)
 }
 
+// FormatMinutes formats a whole number of minutes as "Xh Xm", e.g. "1h 30m", "2h" or "45m"
+func FormatMinutes(mins int) string {
+	sign := ""
+	if mins < 0 {
+		sign = "-"
+		mins = -mins
+	}
+	if mins >= 60 && mins%60 == 0 {
+		return fmt.Sprintf("%s%dh", sign, mins/60)
+	}
+	if mins >= 60 {
+		return fmt.Sprintf("%s%dh %dm", sign, mins/60, mins%60)
+	}
+	return fmt.Sprintf("%s%dm", sign, mins)
+}
+
 // RoundedDuration holds hours and minutes after rounding to 15-minute intervals.
 type RoundedDuration struct {
 	Hours, Mins int
@@ -80,3 +97,85 @@ func RoundDuration(d time.Duration) RoundedDuration {
 func Timestamp() string {
 	return time.Now().Format("15:04:05")
 }
+
+// StartOfDay returns midnight at the beginning of t's day in t's location
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek returns midnight at the beginning of t's week (Sunday)
+func StartOfWeek(t time.Time) time.Time {
+	return StartOfDay(t.AddDate(0, 0, -int(t.Weekday())))
+}
+
+// dateLayouts and clockLayouts are the absolute forms ParseTime accepts
+var (
+	dateLayouts  = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"}
+	clockLayouts = []string{"15:04", "15:04:05", "3pm", "3:04pm"}
+)
+
+// ParseTime reads a time as people write it, relative to now and in now's
+// location. It accepts absolute dates ("2026-10-14 20:00"), a time of day
+// ("20:00", "8pm"), optionally after today, yesterday or a weekday
+// ("yesterday 20:00", "friday 8pm"), and offsets such as "3h ago" or "now".
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	loc := now.Location()
+
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	s = strings.ToLower(s)
+
+	if s == "now" {
+		return now, nil
+	}
+	if ago, ok := strings.CutSuffix(s, " ago"); ok {
+		d, err := time.ParseDuration(strings.ReplaceAll(ago, " ", ""))
+		if err != nil || d < 0 {
+			return time.Time{}, fmt.Errorf("invalid time %q (e.g. 3h ago or 90m ago)", s)
+		}
+		return now.Add(-d), nil
+	}
+
+	day, clock, _ := strings.Cut(s, " ")
+	base, ok := relativeDay(day, now)
+	if !ok {
+		// A bare time of day means today
+		base, clock = StartOfDay(now), s
+	} else if clock == "" {
+		return base, nil
+	}
+
+	for _, layout := range clockLayouts {
+		if t, err := time.ParseInLocation(layout, clock, loc); err == nil {
+			return time.Date(base.Year(), base.Month(), base.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (e.g. \"2026-10-14 20:00\", \"yesterday 20:00\" or \"3h ago\")", s)
+}
+
+// relativeDay resolves today, yesterday or a weekday name to midnight on
+// that day. Weekdays mean the most recent one, which may be today.
+func relativeDay(word string, now time.Time) (time.Time, bool) {
+	today := StartOfDay(now)
+	switch word {
+	case "today":
+		return today, true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	}
+
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		name := strings.ToLower(wd.String())
+		if word == name || word == name[:3] {
+			back := (int(now.Weekday()) - int(wd) + 7) % 7
+			return today.AddDate(0, 0, -back), true
+		}
+	}
+	return time.Time{}, false
+}
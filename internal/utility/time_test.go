@@ -0,0 +1,65 @@
+package utility
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	loc := time.FixedZone("CET", 3600)
+	// A Wednesday evening
+	now := time.Date(2026, 10, 14, 21, 30, 15, 0, loc)
+	date := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2026, month, day, hour, min, 0, 0, loc)
+	}
+
+	for in, want := range map[string]time.Time{
+		"now":                       now,
+		"3h ago":                    now.Add(-3 * time.Hour),
+		"90m ago":                   now.Add(-90 * time.Minute),
+		"1h 30m ago":                now.Add(-90 * time.Minute),
+		"2026-10-12 20:00":          date(10, 12, 20, 0),
+		"2026-10-12T20:00":          date(10, 12, 20, 0),
+		"2026-10-12":                date(10, 12, 0, 0),
+		"2026-10-12T20:00:00+02:00": time.Date(2026, 10, 12, 20, 0, 0, 0, time.FixedZone("", 7200)),
+		"20:00":                     date(10, 14, 20, 0),
+		"8pm":                       date(10, 14, 20, 0),
+		"8:15PM":                    date(10, 14, 20, 15),
+		"today 9:05":                date(10, 14, 9, 5),
+		"yesterday":                 date(10, 13, 0, 0),
+		"yesterday 20:00":           date(10, 13, 20, 0),
+		"Yesterday 11pm":            date(10, 13, 23, 0),
+		"friday 8pm":                date(10, 9, 20, 0),
+		"mon 18:30":                 date(10, 12, 18, 30),
+		// The most recent Wednesday is today
+		"wednesday 10:00": date(10, 14, 10, 0),
+		"  tue  ":         date(10, 13, 0, 0),
+	} {
+		got, err := ParseTime(in, now)
+		if err != nil {
+			t.Errorf("ParseTime(%q): %v", in, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("ParseTime(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestParseTimeErrors(t *testing.T) {
+	now := time.Date(2026, 10, 14, 21, 30, 0, 0, time.UTC)
+	for _, in := range []string{"", "soon", "3 hours ago", "-3h ago", "yesterday noon", "friday 25:00", "2026-13-01"} {
+		if got, err := ParseTime(in, now); err == nil {
+			t.Errorf("ParseTime(%q) = %s, want an error", in, got)
+		}
+	}
+}
+
+func TestStartOfWeek(t *testing.T) {
+	for _, day := range []int{11, 14, 17} {
+		got := StartOfWeek(time.Date(2026, 10, day, 15, 0, 0, 0, time.UTC))
+		if want := time.Date(2026, 10, 11, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+			t.Errorf("StartOfWeek(Oct %d) = %s, want Sunday %s", day, got, want)
+		}
+	}
+}
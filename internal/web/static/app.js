@@ -0,0 +1,183 @@
+"use strict";
+
+const REFRESH_MS = 30000;
+const SVG_NS = "http://www.w3.org/2000/svg";
+
+async function getJSON(path) {
+  const resp = await fetch(path);
+  if (!resp.ok) {
+    throw new Error(`${path}: ${resp.status}`);
+  }
+  return resp.json();
+}
+
+// formatDuration mirrors the CLI's exact minutes, e.g. "2h 15m" or "40m"
+function formatDuration(seconds) {
+  const mins = Math.floor(seconds / 60);
+  if (mins < 60) {
+    return `${mins}m`;
+  }
+  const rest = mins % 60;
+  return rest === 0 ? `${Math.floor(mins / 60)}h` : `${Math.floor(mins / 60)}h ${rest}m`;
+}
+
+function localDate(d) {
+  const pad = (n) => String(n).padStart(2, "0");
+  return `${d.getFullYear()}-${pad(d.getMonth() + 1)}-${pad(d.getDate())}`;
+}
+
+async function loadTotals() {
+  for (const period of ["today", "week", "month"]) {
+    const stats = await getJSON(`stats?period=${period}`);
+    document.getElementById(`total-${period}`).textContent = formatDuration(stats.total_seconds);
+  }
+}
+
+async function loadGames() {
+  const period = document.getElementById("period").value;
+  const stats = await getJSON(period ? `stats?period=${period}` : "stats");
+  const container = document.getElementById("games");
+  container.replaceChildren();
+
+  if (stats.games.length === 0) {
+    const p = document.createElement("p");
+    p.className = "empty";
+    p.textContent = "No sessions in this period.";
+    container.append(p);
+    return;
+  }
+
+  const longest = stats.games[0].total_seconds || 1;
+  for (const game of stats.games) {
+    const row = document.createElement("div");
+    row.className = "bar-row";
+
+    const name = document.createElement("span");
+    name.className = "name";
+    name.textContent = game.game;
+    name.title = game.game;
+
+    const track = document.createElement("div");
+    const bar = document.createElement("div");
+    bar.className = "bar";
+    bar.style.width = `${Math.max(1, (game.total_seconds / longest) * 100)}%`;
+    track.append(bar);
+
+    const value = document.createElement("span");
+    value.className = "value";
+    value.textContent = `${formatDuration(game.total_seconds)} (${game.sessions})`;
+    if (game.imported_seconds) {
+      value.title = `${formatDuration(game.imported_seconds)} imported`;
+    }
+
+    row.append(name, track, value);
+    container.append(row);
+  }
+}
+
+async function loadHeatmap() {
+  const today = new Date();
+  today.setHours(0, 0, 0, 0);
+
+  // Start on the Sunday 52 weeks back so columns line up with weeks
+  const start = new Date(today);
+  start.setDate(start.getDate() - 364 - start.getDay());
+
+  const data = await getJSON(`sessions?from=${localDate(start)}`);
+  const perDay = new Map();
+  for (const s of data.sessions) {
+    const day = localDate(new Date(s.start));
+    perDay.set(day, (perDay.get(day) || 0) + s.duration_seconds);
+  }
+
+  const cell = 12;
+  const gap = 2;
+  const left = 24;
+  const top = 14;
+  const weeks = Math.ceil(((today - start) / 86400000 + 1) / 7);
+
+  const svg = document.createElementNS(SVG_NS, "svg");
+  svg.setAttribute("width", left + weeks * (cell + gap));
+  svg.setAttribute("height", top + 7 * (cell + gap));
+
+  ["Mon", "Wed", "Fri"].forEach((label, i) => {
+    const text = document.createElementNS(SVG_NS, "text");
+    text.setAttribute("x", 0);
+    text.setAttribute("y", top + (i * 2 + 1) * (cell + gap) + cell - 2);
+    text.textContent = label;
+    svg.append(text);
+  });
+
+  let lastMonth = -1;
+  for (let d = new Date(start), i = 0; d <= today; d.setDate(d.getDate() + 1), i++) {
+    const week = Math.floor(i / 7);
+    const x = left + week * (cell + gap);
+
+    if (d.getDay() === 0 && d.getMonth() !== lastMonth) {
+      lastMonth = d.getMonth();
+      const text = document.createElementNS(SVG_NS, "text");
+      text.setAttribute("x", x);
+      text.setAttribute("y", 10);
+      text.textContent = d.toLocaleString(undefined, { month: "short" });
+      svg.append(text);
+    }
+
+    const seconds = perDay.get(localDate(d)) || 0;
+    const rect = document.createElementNS(SVG_NS, "rect");
+    rect.setAttribute("x", x);
+    rect.setAttribute("y", top + d.getDay() * (cell + gap));
+    rect.setAttribute("width", cell);
+    rect.setAttribute("height", cell);
+    rect.setAttribute("fill", heatColor(seconds));
+
+    const title = document.createElementNS(SVG_NS, "title");
+    title.textContent = `${localDate(d)}: ${seconds ? formatDuration(seconds) : "no play"}`;
+    rect.append(title);
+    svg.append(rect);
+  }
+
+  document.getElementById("heatmap").replaceChildren(svg);
+}
+
+function heatColor(seconds) {
+  const hours = seconds / 3600;
+  if (hours === 0) return "#313244";
+  if (hours < 0.5) return "#34476b";
+  if (hours < 1) return "#4a6ba3";
+  if (hours < 2) return "#6a90d6";
+  return "#89b4fa";
+}
+
+async function loadActive() {
+  const el = document.getElementById("active");
+  const active = await getJSON("active");
+
+  if (!active.running) {
+    el.className = "active idle";
+    el.textContent = "Monitor not running";
+    return;
+  }
+  if (active.sessions.length === 0) {
+    el.className = "active idle";
+    el.textContent = "Not playing";
+    return;
+  }
+
+  el.className = "active playing";
+  el.textContent = active.sessions
+    .map((s) => `${s.game} - ${formatDuration(s.elapsed_seconds)}`)
+    .join(", ");
+}
+
+async function refresh() {
+  const results = await Promise.allSettled([loadTotals(), loadGames(), loadHeatmap(), loadActive()]);
+  for (const r of results) {
+    if (r.status === "rejected") {
+      console.error(r.reason);
+    }
+  }
+}
+
+document.getElementById("period").addEventListener("change", () => loadGames().catch(console.error));
+refresh();
+setInterval(refresh, REFRESH_MS);
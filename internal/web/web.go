@@ -0,0 +1,20 @@
+package web
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed static
+var static embed.FS
+
+// Handler serves the embedded dashboard. It expects the JSON API
+// (/sessions, /stats, /active) to be served from the same origin.
+func Handler() http.Handler {
+	sub, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err) // the embedded directory always exists
+	}
+	return http.FileServer(http.FS(sub))
+}